@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gitlab.com/jhinrichsen/adventofcode2019"
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/inputdir"
+)
+
+// draw renders the state of a day in one of several formats to stdout.
+func draw(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("draw", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	day := fs.Int("day", 0, "draw day `n`")
+	format := fs.String("format", "ascii", "output `format`: ascii, pbm, pgm, png or gif")
+	scale := fs.Int("scale", 1, "draw each cell as a square of `n` pixels (png, gif)")
+	delay := fs.Int("delay", 10, "show each frame for `n` hundredths of a second (gif)")
+	frame := fs.Int("frame", -1, "draw frame `i` of a sequence, negative counts from the end")
+	name := fs.String("input", "", "read the input `name` of the day, e.g. main or example1")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 || *name != "" && fs.NArg() == 1 || *scale < 1 || *delay < 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	input := stdin
+	filename := fs.Arg(0)
+	if *name != "" {
+		in, err := inputdir.NewResolver().Find(*day, *name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		filename = in.Path
+	}
+	if filename != "" {
+		f, err := os.Open(filename)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		defer f.Close()
+		input = f
+	}
+	buf, err := io.ReadAll(input)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	frames, err := adventofcode2019.Visualize(*day, buf)
+	if err != nil {
+		fmt.Fprintf(stderr, "day %02d: %v\n", *day, err)
+		return exitError
+	}
+
+	w := bufio.NewWriter(stdout)
+	if *format == "gif" {
+		err = adventofcode2019.WriteGIF(w, frames, *scale, *delay)
+	} else {
+		i := *frame
+		if i < 0 {
+			i += len(frames)
+		}
+		if i < 0 || i >= len(frames) {
+			fmt.Fprintf(stderr, "want frame 0..%d but got %d\n", len(frames)-1, *frame)
+			return exitUsage
+		}
+		f := frames[i]
+		switch *format {
+		case "ascii":
+			err = f.WriteASCII(w)
+		case "pbm":
+			err = f.WritePBM(w)
+		case "pgm":
+			err = f.WritePGM(w)
+		case "png":
+			err = f.WritePNG(w, *scale)
+		default:
+			fmt.Fprintf(stderr, "unknown format %q\n", *format)
+			return exitUsage
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
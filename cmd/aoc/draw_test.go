@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"image/gif"
+	"strings"
+	"testing"
+)
+
+func TestDraw(t *testing.T) {
+	grid := "....#\n#..#.\n#..##\n..#..\n#....\n"
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"ascii", []string{"-day", "24", "-frame", "0"}, exitOK, grid},
+		{"pbm", []string{"-day", "24", "-format", "pbm", "-frame", "0"}, exitOK, "P1\n5 5\n1 1 1 1 0\n0 1 1 0 1\n"},
+		{"png", []string{"-day", "24", "-format", "png"}, exitOK, "\x89PNG"},
+		{"frame", []string{"-day", "24", "-frame", "100"}, exitUsage, ""},
+		{"format", []string{"-day", "24", "-format", "jpeg"}, exitUsage, ""},
+		{"no visualization", []string{"-day", "1"}, exitError, ""},
+		{"bad input", []string{"-day", "24"}, exitError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin := grid
+			if tt.name == "bad input" {
+				stdin = "x"
+			}
+			var stdout, stderr bytes.Buffer
+			code := run(append([]string{"draw"}, tt.args...), strings.NewReader(stdin), &stdout, &stderr)
+			if tt.code != code {
+				t.Fatalf("want exit code %d but got %d (%s)", tt.code, code, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.out) {
+				t.Fatalf("want output to start with %q but got %q", tt.out, stdout.String())
+			}
+		})
+	}
+}
+
+func TestDrawGIF(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"draw", "-day", "24", "-format", "gif", "../../testdata/day24_example.txt"},
+		nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+	anim, err := gif.DecodeAll(&stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) < 2 {
+		t.Fatalf("want an animation but got %d frames", len(anim.Image))
+	}
+}
@@ -0,0 +1,281 @@
+// Command aoc runs the puzzle solvers on arbitrary input files.
+//
+//	aoc run -day 14 [-part 2] [-json] [-input name | input.txt]
+//	aoc run -all [-json] [dir]
+//	aoc total [-workers n] [-count n] [-badge] [-json] [dir]
+//	aoc verify [answers.txt]
+//	aoc inputs [dir]
+//	aoc draw -day 13 [-format gif] [-scale 8] [-input name | input.txt]
+//
+// A single day reads its input from the given file, from the named input
+// with -input, or from stdin. Without -part, both parts are run. With -all,
+// every day is run on its main input, days without input are skipped.
+//
+// Inputs are found by name: the main input of a day is dayNN.txt (name
+// main), other inputs are dayNN_name.txt, e.g. day14_example1.txt. They are
+// searched in dir if given, else in the directories of $AOC_INPUT_DIR, the
+// user cache directory ($XDG_CACHE_HOME/adventofcode2019) and testdata.
+// inputs lists the inputs found for each day.
+//
+// The time to parse the input is printed once per day, each answer is
+// printed together with the time it took to solve. With -json, one JSON
+// object per part is printed instead, holding day, part, answer, parse and
+// solve time in nanoseconds, allocations and the Go and CPU environment.
+//
+// total runs all parts of all days on their main inputs, optionally on
+// several workers in parallel, and prints a table of parse and solve times.
+// The total is the sum of both, wall time is printed separately. With
+// -count, each day is run several times and the fastest run is kept. -badge
+// prints the runtime badge for the README.
+//
+// verify runs every entry of an answers file (default testdata/answers.txt)
+// and prints whether the answer matches. Each line holds day, part, input
+// file relative to the answers file and the expected answer.
+//
+// draw renders the state of a day to stdout as ascii, pbm, pgm, png, or an
+// animated gif of all frames for days that evolve, such as day 13 and 24.
+// Other formats draw the last frame, or the one selected with -frame.
+//
+// Exit codes: 0 success, 1 solver error or wrong answer, 2 usage.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/jhinrichsen/adventofcode2019"
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/bench"
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/inputdir"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: aoc run -day n [-part p] [-json] [-input name | input.txt]")
+	fmt.Fprintln(w, "       aoc run -all [-json] [dir]")
+	fmt.Fprintln(w, "       aoc total [-workers n] [-count n] [-badge] [-json] [dir]")
+	fmt.Fprintln(w, "       aoc verify [answers.txt]")
+	fmt.Fprintln(w, "       aoc inputs [dir]")
+	fmt.Fprintln(w, "       aoc draw -day n [-format f] [-scale n] [-frame i] [-input name | input.txt]")
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "total":
+		return total(args[1:], stdout, stderr)
+	case "verify":
+		return verify(args[1:], stdout, stderr)
+	case "inputs":
+		return listInputs(args[1:], stdout, stderr)
+	case "draw":
+		return draw(args[1:], stdin, stdout, stderr)
+	case "run":
+	default:
+		usage(stderr)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	day := fs.Int("day", 0, "run day `n`, 1..25")
+	part := fs.Int("part", 0, "run part `p` only, 1 or 2, 0 runs both")
+	all := fs.Bool("all", false, "run all days on dayNN.txt files in a directory")
+	jsonOut := fs.Bool("json", false, "print one JSON object per part")
+	name := fs.String("input", "", "read the input `name` of the day, e.g. main or example1")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 || *part < 0 || *part > 2 || *all == (*day != 0) ||
+		*name != "" && (*all || fs.NArg() == 1) {
+		usage(stderr)
+		return exitUsage
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	if *all {
+		return runAll(resolver(fs.Args()), parts, *jsonOut, stdout, stderr)
+	}
+
+	s, err := adventofcode2019.NewSolver(*day)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	input := stdin
+	filename := fs.Arg(0)
+	if *name != "" {
+		in, err := inputdir.NewResolver().Find(*day, *name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		filename = in.Path
+	}
+	if filename != "" {
+		f, err := os.Open(filename)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		defer f.Close()
+		input = f
+	}
+	if *jsonOut {
+		buf, err := io.ReadAll(input)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return runJSON(*day, buf, parts, stdout, stderr)
+	}
+	if _, err := runDay(*day, s, parts, input, stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runJSON solves parts of a day and prints one JSON object per part.
+func runJSON(day int, input []byte, parts []int, stdout, stderr io.Writer) int {
+	t := timeDay(day, input, parts, 1)
+	if t.err != nil {
+		fmt.Fprintln(stderr, t.err)
+		return exitError
+	}
+	if err := writeRecords(stdout, t, bench.CurrentEnv()); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runAll runs all days on their main inputs.
+func runAll(r inputdir.Resolver, parts []int, jsonOut bool, stdout, stderr io.Writer) int {
+	code := exitOK
+	var total time.Duration
+	for _, day := range adventofcode2019.Days() {
+		s, err := adventofcode2019.NewSolver(day)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitError
+			continue
+		}
+		input, err := readInput(r, day)
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(stderr, "day %02d: skipped, %v\n", day, err)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitError
+			continue
+		}
+		if jsonOut {
+			code = max(code, runJSON(day, input, parts, stdout, stderr))
+			continue
+		}
+		d, err := runDay(day, s, parts, bytes.NewReader(input), stdout)
+		total += d
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitError
+		}
+	}
+	if !jsonOut {
+		fmt.Fprintf(stdout, "total: %v\n", total)
+	}
+	return code
+}
+
+// resolver searches the directory in args, or the default directories if
+// there is none.
+func resolver(args []string) inputdir.Resolver {
+	if len(args) == 1 {
+		return inputdir.Resolver{Dirs: args}
+	}
+	return inputdir.NewResolver()
+}
+
+// readInput returns the main input of day.
+func readInput(r inputdir.Resolver, day int) ([]byte, error) {
+	return r.Read(day, inputdir.Main)
+}
+
+// listInputs prints all inputs found per day, and the days that have no main
+// input.
+func listInputs(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 1 {
+		usage(stderr)
+		return exitUsage
+	}
+	r := resolver(args)
+	inputs, err := r.List()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "day\tinput\tpath\t")
+	for _, day := range adventofcode2019.Days() {
+		found := false
+		for _, in := range inputs {
+			if in.Day == day {
+				fmt.Fprintf(tw, "%02d\t%s\t%s\t\n", day, in.Name, in.Path)
+				found = found || in.Name == inputdir.Main
+			}
+		}
+		if !found {
+			fmt.Fprintf(tw, "%02d\t%s\t%s\t\n", day, inputdir.Main, "missing")
+		}
+	}
+	tw.Flush()
+	fmt.Fprintf(stdout, "searched %s\n", strings.Join(r.Dirs, ", "))
+	return exitOK
+}
+
+// runDay parses the input of a day and solves the given parts, prints each
+// answer with its runtime, and returns the accumulated runtime.
+func runDay(day int, s adventofcode2019.Solver, parts []int, input io.Reader,
+	w io.Writer) (time.Duration, error) {
+	start := time.Now()
+	err := s.Parse(input)
+	total := time.Since(start)
+	if err != nil {
+		return total, fmt.Errorf("day %02d: %w", day, err)
+	}
+	fmt.Fprintf(w, "day %02d parse: %v\n", day, total)
+	for _, part := range parts {
+		start := time.Now()
+		answer, err := s.Solve(part == 1)
+		d := time.Since(start)
+		total += d
+		if err != nil {
+			return total, fmt.Errorf("day %02d part %d: %w", day, part, err)
+		}
+		fmt.Fprintf(w, "day %02d part %d: %v (%v)\n", day, part, answer, d)
+	}
+	return total, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/bench"
+)
+
+func TestRunDay(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		code  int
+		out   []string
+	}{
+		{"file", []string{"-day", "1", "../../testdata/day01.txt"}, "", exitOK,
+			[]string{"day 01 part 1: 3231195 (", "day 01 part 2: 4843929 ("}},
+		{"stdin", []string{"-day", "1", "-part", "2"}, "14\n", exitOK,
+			[]string{"day 01 part 2: 2 ("}},
+		{"lines", []string{"-day", "6", "-part", "1"}, "COM)B\nB)C\n", exitOK,
+			[]string{"day 06 part 1: 3 ("}},
+		{"bad input", []string{"-day", "6"}, "COM-B\n", exitError, nil},
+		{"no solver", []string{"-day", "26"}, "", exitUsage, nil},
+		{"bad part", []string{"-day", "1", "-part", "3"}, "", exitUsage, nil},
+		{"no day", nil, "", exitUsage, nil},
+		{"day and all", []string{"-day", "1", "-all"}, "", exitUsage, nil},
+		{"no file", []string{"-day", "1", "nonexistent.txt"}, "", exitUsage, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"run"}, tt.args...)
+			code := run(args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if tt.code != code {
+				t.Fatalf("want exit code %d but got %d (%s)", tt.code, code, stderr.String())
+			}
+			for _, want := range tt.out {
+				if !strings.Contains(stdout.String(), want) {
+					t.Fatalf("want output to contain %q but got %q", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	dir := t.TempDir()
+	for name, input := range map[string]string{
+		"day01.txt": "12\n14\n",
+		"day04.txt": "136818-685979\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(input), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"run", "-all", "-part", "1", dir}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+	for _, want := range []string{"day 01 part 1: 4 (", "day 04 part 1: 1919 (", "total: "} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("want output to contain %q but got %q", want, stdout.String())
+		}
+	}
+	if !strings.Contains(stderr.String(), "day 02: skipped") {
+		t.Fatalf("want skipped day 2 but got %q", stderr.String())
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, strings.NewReader(""), &stdout, &stderr); code != exitUsage {
+		t.Fatalf("want exit code %d but got %d", exitUsage, code)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"run", "-day", "8", "-json", "../../testdata/day08.txt"},
+		strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+	dec := json.NewDecoder(&stdout)
+	for _, want := range []bench.Record{{Day: 8, Part: 1, Answer: "1463"}, {Day: 8, Part: 2, Answer: "GKCKH"}} {
+		var got bench.Record
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if want.Day != got.Day || want.Part != got.Part || want.Answer != got.Answer {
+			t.Fatalf("want %+v but got %+v", want, got)
+		}
+		if got.SolveNs <= 0 || got.ParseNs <= 0 || got.Go == "" || got.CPU == "" {
+			t.Fatalf("want timings and environment but got %+v", got)
+		}
+	}
+	if dec.More() {
+		t.Fatal("want 2 records but got more")
+	}
+}
+
+func TestRunInput(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "day01_small.txt"), []byte("12\n14\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AOC_INPUT_DIR", dir)
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"named", []string{"-day", "1", "-part", "1", "-input", "small"}, exitOK, "day 01 part 1: 4 ("},
+		{"missing", []string{"-day", "1", "-input", "large"}, exitUsage, ""},
+		{"input and file", []string{"-day", "1", "-input", "small", "in.txt"}, exitUsage, ""},
+		{"input and all", []string{"-all", "-input", "small"}, exitUsage, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(append([]string{"run"}, tt.args...), strings.NewReader(""), &stdout, &stderr)
+			if tt.code != code {
+				t.Fatalf("want exit code %d but got %d (%s)", tt.code, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.out) {
+				t.Fatalf("want output to contain %q but got %q", tt.out, stdout.String())
+			}
+		})
+	}
+}
+
+func TestInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"day01.txt", "day01_example.txt", "day02_example.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"inputs", dir}, nil, &stdout, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+	for _, want := range []string{
+		"01   main     " + filepath.Join(dir, "day01.txt"),
+		"01   example  " + filepath.Join(dir, "day01_example.txt"),
+		"02   example  " + filepath.Join(dir, "day02_example.txt"),
+		"02   main     missing",
+		"04   main     missing",
+		"searched " + dir,
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("want output to contain %q but got\n%s", want, stdout.String())
+		}
+	}
+}
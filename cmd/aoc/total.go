@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/jhinrichsen/adventofcode2019"
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/bench"
+)
+
+// timing holds the measurements of one day.
+type timing struct {
+	day         int
+	parse       time.Duration
+	parseAllocs uint64
+	parseBytes  uint64
+	parts       []partTiming
+	err         error
+}
+
+// partTiming holds the answer of one part and the time and memory it took to
+// solve.
+type partTiming struct {
+	part   int
+	answer adventofcode2019.Answer
+	solve  time.Duration
+	allocs uint64
+	bytes  uint64
+}
+
+// parts returns the parts of day, the last day has only one puzzle.
+func parts(day int) []int {
+	if day == 25 {
+		return []int{1}
+	}
+	return []int{1, 2}
+}
+
+// total runs all parts of all days on their input files, distributed across
+// workers, and prints a table of parse and solve times plus their total.
+func total(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("total", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	workers := flags.Int("workers", 1, "run `n` days in parallel")
+	count := flags.Int("count", 1, "run each day `n` times and keep the fastest")
+	badge := flags.Bool("badge", false, "print the README runtime badge")
+	jsonOut := flags.Bool("json", false, "print one JSON object per part instead of a table")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 1 || *workers < 1 || *count < 1 {
+		usage(stderr)
+		return exitUsage
+	}
+	r := resolver(flags.Args())
+
+	code := exitOK
+	inputs := make(map[int][]byte)
+	var days []int
+	for _, day := range adventofcode2019.Days() {
+		input, err := readInput(r, day)
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(stderr, "day %02d: skipped, %v\n", day, err)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitError
+			continue
+		}
+		inputs[day] = input
+		days = append(days, day)
+	}
+
+	start := time.Now()
+	timings := timeDays(days, inputs, *workers, *count)
+	wall := time.Since(start)
+
+	if *jsonOut {
+		env := bench.CurrentEnv()
+		for _, t := range timings {
+			if t.err != nil {
+				fmt.Fprintln(stderr, t.err)
+				code = exitError
+				continue
+			}
+			if err := writeRecords(stdout, t, env); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitError
+			}
+		}
+		return code
+	}
+
+	var parse, solve time.Duration
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "day\tpart\tparse\tsolve\tanswer\t")
+	for _, t := range timings {
+		if t.err != nil {
+			fmt.Fprintln(stderr, t.err)
+			code = exitError
+			continue
+		}
+		parse += t.parse
+		for i, p := range t.parts {
+			solve += p.solve
+			var d string
+			if i == 0 {
+				d = t.parse.String()
+			}
+			fmt.Fprintf(tw, "%02d\t%d\t%s\t%v\t%v\t\n", t.day, p.part, d, p.solve, p.answer)
+		}
+	}
+	tw.Flush()
+	fmt.Fprintf(stdout, "total: %v (parse %v, solve %v), wall %v with %d workers\n",
+		parse+solve, parse, solve, wall, *workers)
+	if *badge {
+		fmt.Fprintf(stdout, "image:https://img.shields.io/badge/runtime-%.2fs-blue[\"runtime\"]\n",
+			(parse + solve).Seconds())
+	}
+	return code
+}
+
+// timeDays measures days on their inputs using the given number of workers,
+// results are in the order of days.
+func timeDays(days []int, inputs map[int][]byte, workers, count int) []timing {
+	timings := make([]timing, len(days))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				timings[i] = timeDay(days[i], inputs[days[i]], parts(days[i]), count)
+			}
+		}()
+	}
+	for i := range days {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return timings
+}
+
+// timeDay parses and solves parts of a day count times and keeps the fastest
+// parse and solve times.
+func timeDay(day int, input []byte, parts []int, count int) timing {
+	t := timing{day: day}
+	for n := range count {
+		s, err := adventofcode2019.NewSolver(day)
+		if err != nil {
+			t.err = err
+			return t
+		}
+		d, allocs, allocBytes := measure(func() {
+			err = s.Parse(bytes.NewReader(input))
+		})
+		if err != nil {
+			t.err = fmt.Errorf("day %02d: %w", day, err)
+			return t
+		}
+		if n == 0 {
+			t.parseAllocs, t.parseBytes = allocs, allocBytes
+		}
+		if n == 0 || d < t.parse {
+			t.parse = d
+		}
+		for i, part := range parts {
+			var answer adventofcode2019.Answer
+			d, allocs, allocBytes := measure(func() {
+				answer, err = s.Solve(part == 1)
+			})
+			if err != nil {
+				t.err = fmt.Errorf("day %02d part %d: %w", day, part, err)
+				return t
+			}
+			if n == 0 {
+				t.parts = append(t.parts, partTiming{part, answer, d, allocs, allocBytes})
+			} else if d < t.parts[i].solve {
+				t.parts[i].solve = d
+			}
+		}
+	}
+	return t
+}
+
+// measure calls f and returns its duration and the number and bytes of heap
+// allocations. Allocations are counted for the whole process, they are only
+// exact if nothing else runs concurrently.
+func measure(f func()) (d time.Duration, allocs, bytes uint64) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	f()
+	d = time.Since(start)
+	runtime.ReadMemStats(&after)
+	return d, after.Mallocs - before.Mallocs, after.TotalAlloc - before.TotalAlloc
+}
+
+// writeRecords writes one JSON object per part of t.
+func writeRecords(w io.Writer, t timing, env bench.Env) error {
+	enc := json.NewEncoder(w)
+	for _, p := range t.parts {
+		err := enc.Encode(bench.Record{
+			Day:         t.day,
+			Part:        p.part,
+			Answer:      p.answer.String(),
+			ParseNs:     t.parse.Nanoseconds(),
+			SolveNs:     p.solve.Nanoseconds(),
+			ParseAllocs: t.parseAllocs,
+			ParseBytes:  t.parseBytes,
+			Allocs:      p.allocs,
+			Bytes:       p.bytes,
+			Env:         env,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/bench"
+)
+
+func TestTotal(t *testing.T) {
+	dir := t.TempDir()
+	for name, input := range map[string]string{
+		"day01.txt": "12\n14\n",
+		"day04.txt": "136818-685979\n",
+		"day06.txt": "COM)B\nB)C\nC)YOU\nB)SAN\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(input), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, workers := range []string{"1", "3"} {
+		t.Run(workers, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"total", "-workers", workers, "-count", "2", "-badge", dir},
+				strings.NewReader(""), &stdout, &stderr)
+			if code != exitOK {
+				t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+			}
+			out := stdout.String()
+			for _, want := range []string{"day  part", "1919", "1291", "total: ", "workers",
+				"image:https://img.shields.io/badge/runtime-"} {
+				if !strings.Contains(out, want) {
+					t.Fatalf("want output to contain %q but got %q", want, out)
+				}
+			}
+			// days are printed in order regardless of the number of workers
+			i1, i4, i6 := strings.Index(out, "\n   01"), strings.Index(out, "\n   04"), strings.Index(out, "\n   06")
+			if i1 < 0 || i1 > i4 || i4 > i6 {
+				t.Fatalf("want days 1, 4, 6 in order but got %q", out)
+			}
+			if !strings.Contains(stderr.String(), "day 02: skipped") {
+				t.Fatalf("want skipped day 2 but got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func TestTotalJSON(t *testing.T) {
+	dir := t.TempDir()
+	for name, input := range map[string]string{
+		"day01.txt": "12\n14\n",
+		"day04.txt": "136818-685979\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(input), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"total", "-json", dir}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+	var answers []string
+	dec := json.NewDecoder(&stdout)
+	for dec.More() {
+		var r bench.Record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		answers = append(answers, fmt.Sprintf("%d/%d=%s", r.Day, r.Part, r.Answer))
+	}
+	want := "1/1=4 1/2=4 4/1=1919 4/2=1291"
+	if got := strings.Join(answers, " "); want != got {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
+
+func TestTotalError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "day06.txt"), []byte("COM-B\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"total", dir}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitError {
+		t.Fatalf("want exit code %d but got %d", exitError, code)
+	}
+	if !strings.Contains(stderr.String(), "day 06:") {
+		t.Fatalf("want error for day 6 but got %q", stderr.String())
+	}
+}
+
+func TestTotalUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"total", "-workers", "0"}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitUsage {
+		t.Fatalf("want exit code %d but got %d", exitUsage, code)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/jhinrichsen/adventofcode2019"
+)
+
+// answer is one line of an answers file.
+type answer struct {
+	day, part int
+	input     string // relative to the answers file
+	want      string
+}
+
+// parseAnswers reads lines of day, part, input file and answer separated by
+// whitespace. Empty lines and lines starting with '#' are ignored.
+func parseAnswers(r io.Reader) ([]answer, error) {
+	var answers []answer
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: want day, part, input and answer but got %q", n, line)
+		}
+		day, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: day: %w", n, err)
+		}
+		part, err := strconv.Atoi(fields[1])
+		if err != nil || part < 1 || part > 2 {
+			return nil, fmt.Errorf("line %d: want part 1 or 2 but got %q", n, fields[1])
+		}
+		answers = append(answers, answer{day, part, fields[2], fields[3]})
+	}
+	return answers, sc.Err()
+}
+
+// verify runs every entry of an answers file and prints whether the answer
+// matches, and the time to parse and solve.
+func verify(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 1 {
+		usage(stderr)
+		return exitUsage
+	}
+	filename := filepath.Join("testdata", "answers.txt")
+	if flags.NArg() == 1 {
+		filename = flags.Arg(0)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	answers, err := parseAnswers(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", filename, err)
+		return exitUsage
+	}
+
+	var passed, failed int
+	var total time.Duration
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "day\tpart\tinput\tresult\ttime\t")
+	for _, a := range answers {
+		got, d, err := check(filepath.Dir(filename), a)
+		total += d
+		var result string
+		switch {
+		case err != nil:
+			result = "FAIL " + err.Error()
+		case got != a.want:
+			result = fmt.Sprintf("FAIL want %s but got %s", a.want, got)
+		default:
+			result = "ok " + got
+		}
+		if err != nil || got != a.want {
+			failed++
+		} else {
+			passed++
+		}
+		fmt.Fprintf(tw, "%02d\t%d\t%s\t%s\t%v\t\n", a.day, a.part, a.input, result, d)
+	}
+	tw.Flush()
+	fmt.Fprintf(stdout, "%d passed, %d failed in %v\n", passed, failed, total)
+	if failed > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// check solves one entry of an answers file, the input is relative to dir.
+// The duration includes parsing.
+func check(dir string, a answer) (string, time.Duration, error) {
+	s, err := adventofcode2019.NewSolver(a.day)
+	if err != nil {
+		return "", 0, err
+	}
+	input, err := os.ReadFile(filepath.Join(dir, a.input))
+	if err != nil {
+		return "", 0, err
+	}
+	start := time.Now()
+	if err := s.Parse(bytes.NewReader(input)); err != nil {
+		return "", time.Since(start), err
+	}
+	got, err := s.Solve(a.part == 1)
+	return got.String(), time.Since(start), err
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/jhinrichsen/adventofcode2019"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("day01.txt", "12\n14\n")
+	write("day04.txt", "136818-685979\n")
+	write("answers.txt", "# comment\n1 1 day01.txt 4\n\n4 2 day04.txt 1291\n")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"verify", filepath.Join(dir, "answers.txt")}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s%s)", exitOK, code, stdout.String(), stderr.String())
+	}
+	for _, want := range []string{"ok 4", "ok 1291", "2 passed, 0 failed"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("want output to contain %q but got %q", want, stdout.String())
+		}
+	}
+
+	write("answers.txt", "1 1 day01.txt 5\n1 1 day02.txt 1\n26 1 day01.txt 1\n")
+	stdout.Reset()
+	code = run([]string{"verify", filepath.Join(dir, "answers.txt")}, nil, &stdout, &stderr)
+	if code != exitError {
+		t.Fatalf("want exit code %d but got %d", exitError, code)
+	}
+	for _, want := range []string{"FAIL want 5 but got 4", "FAIL open", "FAIL no solver for day 26", "0 passed, 3 failed"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("want output to contain %q but got %q", want, stdout.String())
+		}
+	}
+}
+
+func TestVerifyUsage(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "answers.txt")
+	if err := os.WriteFile(filename, []byte("1 3 day01.txt 4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"verify", filename},
+		{"verify", filepath.Join(dir, "nonexistent.txt")},
+		{"verify", "a", "b"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, nil, &stdout, &stderr); code != exitUsage {
+			t.Fatalf("%v: want exit code %d but got %d", args, exitUsage, code)
+		}
+	}
+}
+
+// TestAnswersFile makes sure the answers file covers every part of every day.
+func TestAnswersFile(t *testing.T) {
+	f, err := os.Open("../../testdata/answers.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	answers, err := parseAnswers(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := make(map[[2]int]bool)
+	for _, a := range answers {
+		have[[2]int{a.day, a.part}] = true
+	}
+	for _, day := range adventofcode2019.Days() {
+		for _, part := range parts(day) {
+			if !have[[2]int{day, part}] {
+				t.Errorf("no answer for day %d part %d", day, part)
+			}
+		}
+	}
+}
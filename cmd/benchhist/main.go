@@ -0,0 +1,344 @@
+// Command benchhist keeps a history of benchmark runs and detects
+// regressions.
+//
+//	go test -run=^$ -bench=Day..Part.$ -benchmem -count 10 | benchhist record
+//	benchhist compare [-threshold 5] [-alpha 0.05]
+//	go test -run=^$ -bench=Day..Part.$ -benchmem | benchhist json
+//
+// record appends the benchmark output read from stdin as one JSON line to
+// the store (default benches/history.jsonl), keyed by CPU name, Go version
+// and git commit.
+//
+// compare compares the latest run against the previous run on the same CPU.
+// Like benchstat, a change in ns/op only counts if the Mann-Whitney U test
+// finds it significant, which needs several samples per benchmark (-count).
+// Any DayXXPartY that is significantly slower by more than threshold percent
+// is a regression.
+//
+// json converts benchmark output read from stdin into one JSON object per
+// DayXXPartY, in the same format as aoc run -json. The benchmarks measure
+// parsing and solving together, both are reported as solve_ns.
+//
+// Exit codes: 0 success, 1 error, 2 usage, 3 regression.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/bench"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitRegression
+)
+
+const defaultStore = "benches/history.jsonl"
+
+// record is one benchmark run in the store.
+type record struct {
+	Time       time.Time          `json:"time"`
+	CPU        string             `json:"cpu"`
+	GOOS       string             `json:"goos"`
+	GOARCH     string             `json:"goarch"`
+	Go         string             `json:"go"`
+	Commit     string             `json:"commit"`
+	Benchmarks map[string]samples `json:"benchmarks"`
+}
+
+// samples holds all samples of one benchmark.
+type samples struct {
+	NsPerOp     []float64 `json:"ns_per_op"`
+	BytesPerOp  []uint64  `json:"bytes_per_op"`
+	AllocsPerOp []uint64  `json:"allocs_per_op"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: benchhist record [-store file] [-commit id] [-go version] < bench.txt")
+	fmt.Fprintln(w, "       benchhist compare [-store file] [-cpu name] [-threshold percent] [-alpha p]")
+	fmt.Fprintln(w, "       benchhist json [-go version] < bench.txt")
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "record":
+		return recordCmd(args[1:], stdin, stderr)
+	case "compare":
+		return compareCmd(args[1:], stdout, stderr)
+	case "json":
+		return jsonCmd(args[1:], stdin, stdout, stderr)
+	}
+	usage(stderr)
+	return exitUsage
+}
+
+func recordCmd(args []string, stdin io.Reader, stderr io.Writer) int {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	store := fs.String("store", defaultStore, "append to history `file`")
+	commit := fs.String("commit", "", "git commit `id`, default is the current HEAD")
+	goVersion := fs.String("go", runtime.Version(), "Go `version` of the benchmark run")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	out, err := bench.Parse(stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if out.CPU == "" {
+		fmt.Fprintln(stderr, "no 'cpu: ' line found in input")
+		return exitError
+	}
+	if len(out.Results) == 0 {
+		fmt.Fprintln(stderr, "no benchmark results found in input")
+		return exitError
+	}
+	if *commit == "" {
+		*commit = gitCommit()
+	}
+
+	r := record{
+		Time:       time.Now().UTC(),
+		CPU:        bench.SanitizeCPUName(out.CPU),
+		GOOS:       out.GOOS,
+		GOARCH:     out.GOARCH,
+		Go:         *goVersion,
+		Commit:     *commit,
+		Benchmarks: make(map[string]samples),
+	}
+	for _, res := range out.Results {
+		s := r.Benchmarks[res.Name]
+		s.NsPerOp = append(s.NsPerOp, res.NsPerOp)
+		s.BytesPerOp = append(s.BytesPerOp, res.BytesPerOp)
+		s.AllocsPerOp = append(s.AllocsPerOp, res.AllocsPerOp)
+		r.Benchmarks[res.Name] = s
+	}
+	if err := appendRecord(*store, r); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+var dayPart = regexp.MustCompile(`^Day(\d\d)Part(\d)$`)
+
+func jsonCmd(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("json", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	goVersion := fs.String("go", runtime.Version(), "Go `version` of the benchmark run")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	out, err := bench.Parse(stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	env := bench.Env{
+		GOOS:   out.GOOS,
+		GOARCH: out.GOARCH,
+		Go:     *goVersion,
+		CPU:    bench.SanitizeCPUName(out.CPU),
+	}
+	enc := json.NewEncoder(stdout)
+	for _, res := range out.Results {
+		m := dayPart.FindStringSubmatch(res.Name)
+		if m == nil {
+			continue
+		}
+		day, _ := strconv.Atoi(m[1])
+		part, _ := strconv.Atoi(m[2])
+		err := enc.Encode(bench.Record{
+			Day:     day,
+			Part:    part,
+			SolveNs: int64(math.Round(res.NsPerOp)),
+			Allocs:  res.AllocsPerOp,
+			Bytes:   res.BytesPerOp,
+			Env:     env,
+		})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+// gitCommit returns the short id of the current git HEAD, or "unknown".
+func gitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func appendRecord(filename string, r record) error {
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(buf, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readRecords(filename string) ([]record, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		var r record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, n, err)
+		}
+		records = append(records, r)
+	}
+	return records, sc.Err()
+}
+
+func compareCmd(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	store := fs.String("store", defaultStore, "history `file`")
+	cpu := fs.String("cpu", "", "compare runs on CPU `name`, default is the CPU of the latest run")
+	threshold := fs.Float64("threshold", 5, "regression `percent` in ns/op")
+	alpha := fs.Float64("alpha", 0.05, "significance level `p`")
+	filter := fs.String("filter", `^Day\d\dPart\d$`, "benchmarks `regexp` that fail on regression")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	re, err := regexp.Compile(*filter)
+	if err != nil || fs.NArg() != 0 || *threshold < 0 || *alpha <= 0 || *alpha >= 1 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	records, err := readRecords(*store)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if len(records) == 0 {
+		fmt.Fprintf(stderr, "no runs in %s\n", *store)
+		return exitError
+	}
+	if *cpu == "" {
+		*cpu = records[len(records)-1].CPU
+	}
+	var runs []record
+	for _, r := range records {
+		if r.CPU == *cpu {
+			runs = append(runs, r)
+		}
+	}
+	if len(runs) < 2 {
+		fmt.Fprintf(stderr, "no baseline for cpu %s, need two runs but got %d\n", *cpu, len(runs))
+		return exitOK
+	}
+	old, cur := runs[len(runs)-2], runs[len(runs)-1]
+	fmt.Fprintf(stdout, "cpu: %s\nold: %s %s %s\nnew: %s %s %s\n", *cpu,
+		old.Commit, old.Go, old.Time.Format(time.RFC3339),
+		cur.Commit, cur.Go, cur.Time.Format(time.RFC3339))
+	if compare(stdout, old, cur, re, *threshold, *alpha) {
+		return exitRegression
+	}
+	return exitOK
+}
+
+// compare prints a table of the benchmarks in both runs and reports whether
+// a benchmark matching re regressed.
+func compare(w io.Writer, old, cur record, re *regexp.Regexp, threshold, alpha float64) bool {
+	var names []string
+	for name := range cur.Benchmarks {
+		if _, ok := old.Benchmarks[name]; ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	regressed := false
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "name\told ns/op\tnew ns/op\tdelta\tp\t\t")
+	for _, name := range names {
+		x, y := old.Benchmarks[name].NsPerOp, cur.Benchmarks[name].NsPerOp
+		mx, my := median(x), median(y)
+		p := bench.UTest(x, y)
+		delta := "~"
+		var verdict string
+		if p < alpha && mx > 0 {
+			d := (my - mx) / mx * 100
+			delta = fmt.Sprintf("%+.2f%%", d)
+			if d > threshold && re.MatchString(name) {
+				verdict = "regression"
+				regressed = true
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%.4g\t%.4g\t%s\tp=%.3f n=%d+%d\t%s\t\n",
+			name, mx, my, delta, p, len(x), len(y), verdict)
+	}
+	tw.Flush()
+	return regressed
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := slices.Clone(xs)
+	slices.Sort(s)
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
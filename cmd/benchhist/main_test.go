@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// benchOutput returns go test -bench output with 5 samples per benchmark.
+func benchOutput(day01, day02 float64) string {
+	var sb strings.Builder
+	sb.WriteString("goos: linux\ngoarch: amd64\ncpu: Test CPU @ 1.00GHz\n")
+	for i := range 5 {
+		fmt.Fprintf(&sb, "BenchmarkDay01Part1-8 1000 %.1f ns/op 0 B/op 0 allocs/op\n", day01+float64(i))
+		fmt.Fprintf(&sb, "BenchmarkDay02Part1-8 1000 %.1f ns/op 16 B/op 1 allocs/op\n", day02+float64(i))
+	}
+	sb.WriteString("PASS\n")
+	return sb.String()
+}
+
+func recordRun(t *testing.T, store, commit, input string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"record", "-store", store, "-commit", commit},
+		strings.NewReader(input), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name         string
+		day01, day02 float64
+		code         int
+		out          []string
+	}{
+		{"unchanged", 100, 200, exitOK, []string{"Day01Part1", "~"}},
+		{"faster", 50, 200, exitOK, []string{"-49.02%"}},
+		{"slower", 100, 300, exitRegression, []string{"+49.50%", "regression"}},
+		// significant but below threshold
+		{"noise", 103, 200, exitOK, []string{"+2.94%"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := filepath.Join(t.TempDir(), "history.jsonl")
+			recordRun(t, store, "old", benchOutput(100, 200))
+			recordRun(t, store, "new", benchOutput(tt.day01, tt.day02))
+
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"compare", "-store", store}, nil, &stdout, &stderr)
+			if tt.code != code {
+				t.Fatalf("want exit code %d but got %d (%s%s)", tt.code, code, stdout.String(), stderr.String())
+			}
+			for _, want := range append(tt.out, "cpu: Test-CPU-@-100GHz", "old: old ", "new: new ") {
+				if !strings.Contains(stdout.String(), want) {
+					t.Fatalf("want output to contain %q but got %q", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCompareNoBaseline(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "history.jsonl")
+	recordRun(t, store, "old", benchOutput(100, 200))
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"compare", "-store", store}, nil, &stdout, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d", exitOK, code)
+	}
+	if !strings.Contains(stderr.String(), "no baseline") {
+		t.Fatalf("want no baseline but got %q", stderr.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	input := benchOutput(100, 200) + "BenchmarkParse-8 1000 5.0 ns/op 0 B/op 0 allocs/op\n"
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"json", "-go", "go1.25"}, strings.NewReader(input), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("want exit code %d but got %d (%s)", exitOK, code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("want 10 records but got %d", len(lines))
+	}
+	want := `{"day":2,"part":1,"parse_ns":0,"solve_ns":200,"parse_allocs":0,"parse_bytes":0,` +
+		`"allocs":1,"bytes":16,"goos":"linux","goarch":"amd64","go":"go1.25","cpu":"Test-CPU-@-100GHz"}`
+	if want != lines[1] {
+		t.Fatalf("want %s but got %s", want, lines[1])
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "history.jsonl")
+	for _, input := range []string{"", "cpu: x\nPASS\n", "BenchmarkDay01Part1 1 1 ns/op\n"} {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"record", "-store", store}, strings.NewReader(input), &stdout, &stderr)
+		if code != exitError {
+			t.Fatalf("%q: want exit code %d but got %d", input, exitError, code)
+		}
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, nil, &stdout, &stderr); code != exitUsage {
+		t.Fatalf("want exit code %d but got %d", exitUsage, code)
+	}
+}
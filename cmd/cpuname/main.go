@@ -5,26 +5,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
-)
-
-func sanitizeCPUName(input string) string {
-	var result strings.Builder
 
-	for _, r := range input {
-		switch {
-		case r == ' ':
-			result.WriteRune('-')
-		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
-			result.WriteRune(r)
-		case r == '@' || r == '_' || r == '-':
-			result.WriteRune(r)
-		default:
-			// Skip any other character
-		}
-	}
-
-	return result.String()
-}
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/bench"
+)
 
 func main() {
 	scanner := bufio.NewScanner(os.Stdin)
@@ -33,7 +16,7 @@ func main() {
 		if strings.HasPrefix(line, "cpu: ") {
 			cpuName := strings.TrimPrefix(line, "cpu: ")
 			cpuName = strings.TrimSpace(cpuName)
-			fmt.Print(sanitizeCPUName(cpuName))
+			fmt.Print(bench.SanitizeCPUName(cpuName))
 			return
 		}
 	}
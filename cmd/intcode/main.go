@@ -0,0 +1,273 @@
+// Command intcode runs arbitrary Intcode programs.
+//
+//	intcode run [flags] prog.txt [input...]
+//
+// Numeric inputs are taken from the command line, or from stdin (separated
+// by whitespace or commas) if none are given. In ASCII mode, stdin is fed
+// byte by byte and outputs in the ASCII range are printed as characters.
+//
+// With -cover, a listing of the program annotated with the number of
+// executions per instruction is written to a file, as HTML if the filename
+// ends in .html.
+//
+// Exit codes: 0 program halted, 1 fault, 2 usage, 3 input starvation,
+// 4 step limit exceeded.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gitlab.com/jhinrichsen/adventofcode2019"
+)
+
+const (
+	exitHalt = iota
+	exitFault
+	exitUsage
+	exitStarved
+	exitMaxSteps
+)
+
+// patches collects repeated -set addr=value flags.
+type patches [][2]int
+
+func (a *patches) String() string {
+	var ss []string
+	for _, p := range *a {
+		ss = append(ss, fmt.Sprintf("%d=%d", p[0], p[1]))
+	}
+	return strings.Join(ss, ",")
+}
+
+func (a *patches) Set(s string) error {
+	addr, val, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("want addr=value but got %q", s)
+	}
+	n, err := strconv.Atoi(addr)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("negative address %d", n)
+	}
+	if n >= adventofcode2019.MaxMem {
+		return fmt.Errorf("address %d out of memory", n)
+	}
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return err
+	}
+	*a = append(*a, [2]int{n, v})
+	return nil
+}
+
+// inputs supplies values for input instructions.
+type inputs interface {
+	next() (int, error)
+}
+
+// argInputs feeds numbers from the command line.
+type argInputs []int
+
+func (a *argInputs) next() (int, error) {
+	if len(*a) == 0 {
+		return 0, io.EOF
+	}
+	n := (*a)[0]
+	*a = (*a)[1:]
+	return n, nil
+}
+
+// numberInputs reads whitespace or comma separated numbers.
+type numberInputs struct {
+	sc      *bufio.Scanner
+	pending []string
+}
+
+func (a *numberInputs) next() (int, error) {
+	for len(a.pending) == 0 {
+		if !a.sc.Scan() {
+			if err := a.sc.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		for _, f := range strings.Split(a.sc.Text(), ",") {
+			if f != "" {
+				a.pending = append(a.pending, f)
+			}
+		}
+	}
+	s := a.pending[0]
+	a.pending = a.pending[1:]
+	return strconv.Atoi(s)
+}
+
+// asciiInputs reads single bytes.
+type asciiInputs struct {
+	r *bufio.Reader
+}
+
+func (a asciiInputs) next() (int, error) {
+	b, err := a.r.ReadByte()
+	return int(b), err
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: intcode run [flags] prog.txt [input...]")
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "run" {
+		usage(stderr)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ascii := fs.Bool("ascii", false, "ASCII mode: feed stdin as characters, print outputs < 128 as characters")
+	trace := fs.Bool("trace", false, "print each instruction to stderr before executing it")
+	maxSteps := fs.Int("max-steps", 0, "abort after `n` instructions, 0 means no limit")
+	cover := fs.String("cover", "", "write coverage listing to `file`, HTML if it ends in .html")
+	var set patches
+	fs.Var(&set, "set", "patch memory before running, `addr=value`, may be repeated")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() < 1 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	prog, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	ic, err := adventofcode2019.NewIntcode(prog)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	for _, p := range set {
+		ic.SetMem(p[0], p[1])
+	}
+
+	var in inputs
+	switch {
+	case fs.NArg() > 1:
+		var ns argInputs
+		for _, s := range fs.Args()[1:] {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitUsage
+			}
+			ns = append(ns, n)
+		}
+		in = &ns
+	case *ascii:
+		in = asciiInputs{bufio.NewReader(stdin)}
+	default:
+		sc := bufio.NewScanner(stdin)
+		sc.Split(bufio.ScanWords)
+		in = &numberInputs{sc: sc}
+	}
+
+	var cov *adventofcode2019.Coverage
+	if *cover != "" {
+		cov = adventofcode2019.NewCoverage()
+		ic.Cover(cov)
+	}
+
+	w := bufio.NewWriter(stdout)
+	code := execute(ic, in, *ascii, *trace, *maxSteps, w, stderr)
+	w.Flush()
+
+	if cov != nil {
+		if err := writeCoverage(*cover, cov, ic); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+	return code
+}
+
+// writeCoverage writes a text or HTML coverage listing to filename.
+func writeCoverage(filename string, cov *adventofcode2019.Coverage, ic *adventofcode2019.Intcode) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if filepath.Ext(filename) == ".html" {
+		err = cov.WriteHTML(f, ic)
+	} else {
+		err = cov.WriteText(f, ic)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// execute runs ic until it halts, faults, starves or exceeds maxSteps, and
+// returns the matching exit code.
+func execute(ic *adventofcode2019.Intcode, in inputs, ascii, trace bool, maxSteps int,
+	w *bufio.Writer, stderr io.Writer) int {
+	steps := 0
+	for {
+		if maxSteps > 0 && steps >= maxSteps {
+			w.Flush()
+			fmt.Fprintf(stderr, "step limit %d exceeded at address %d\n", maxSteps, ic.IP())
+			return exitMaxSteps
+		}
+		if trace {
+			if instr, ok := ic.Decode(ic.IP()); ok {
+				fmt.Fprintf(stderr, "%8d %6d  %s\n", steps, ic.IP(), instr)
+			}
+		}
+		steps++
+		switch ic.Step() {
+		case adventofcode2019.NeedsInput:
+			n, err := in.next()
+			if errors.Is(err, io.EOF) {
+				w.Flush()
+				fmt.Fprintf(stderr, "program needs input at address %d\n", ic.IP())
+				return exitStarved
+			}
+			if err != nil {
+				w.Flush()
+				fmt.Fprintln(stderr, err)
+				return exitUsage
+			}
+			ic.Input(n)
+		case adventofcode2019.HasOutput:
+			out := ic.Output()
+			if ascii && out >= 0 && out < 128 {
+				w.WriteByte(byte(out))
+			} else {
+				fmt.Fprintln(w, out)
+			}
+		case adventofcode2019.Halted:
+			return exitHalt
+		case adventofcode2019.Faulted:
+			w.Flush()
+			fmt.Fprintln(stderr, ic.Err())
+			return exitFault
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProg(t *testing.T, prog string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "prog.txt")
+	if err := os.WriteFile(filename, []byte(prog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestRun(t *testing.T) {
+	// echo: read one number, write it
+	echo := "3,0,4,0,99"
+	// print "Hi\n", then halt
+	hello := "104,72,104,105,104,10,99"
+	// output mem[9], which is patched by -set
+	patched := "4,9,99,0,0,0,0,0,0,7"
+	// jump to itself forever
+	loop := "1105,1,0"
+
+	tests := []struct {
+		name   string
+		prog   string
+		flags  []string
+		inputs []string
+		stdin  string
+		code   int
+		out    string
+	}{
+		{"args", echo, nil, []string{"42"}, "", exitHalt, "42\n"},
+		{"stdin", echo, nil, nil, " 17\n", exitHalt, "17\n"},
+		{"stdin comma", echo, nil, nil, "-3,4", exitHalt, "-3\n"},
+		{"ascii out", hello, []string{"-ascii"}, nil, "", exitHalt, "Hi\n"},
+		{"ascii in", echo, []string{"-ascii"}, nil, "A", exitHalt, "A"},
+		{"starved", echo, nil, nil, "", exitStarved, ""},
+		{"fault", "42", nil, nil, "", exitFault, ""},
+		{"negative address", "1,-5,0,0,99", nil, nil, "", exitFault, ""},
+		{"truncated", "1,0,0", nil, nil, "", exitFault, ""},
+		{"max steps", loop, []string{"-max-steps", "100"}, nil, "", exitMaxSteps, ""},
+		{"set", patched, []string{"-set", "9=2", "-set", "0=4"}, nil, "", exitHalt, "2\n"},
+		{"bad set", patched, []string{"-set", "9"}, nil, "", exitUsage, ""},
+		{"set too far", patched, []string{"-set", "9000000000000000000=1"}, nil, "", exitUsage, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"run"}, tt.flags...)
+			args = append(args, writeProg(t, tt.prog))
+			args = append(args, tt.inputs...)
+			var stdout, stderr bytes.Buffer
+			code := run(args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if tt.code != code {
+				t.Fatalf("want exit code %d but got %d (%s)", tt.code, code, stderr.String())
+			}
+			if tt.out != stdout.String() {
+				t.Fatalf("want output %q but got %q", tt.out, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunDay09(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"run", "../../testdata/day09.txt", "1"}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitHalt {
+		t.Fatalf("want exit code %d but got %d (%s)", exitHalt, code, stderr.String())
+	}
+	want := "2436480432\n"
+	if want != stdout.String() {
+		t.Fatalf("want %q but got %q", want, stdout.String())
+	}
+}
+
+func TestRunTrace(t *testing.T) {
+	filename := writeProg(t, "1101,2,3,5,99,0")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"run", "-trace", filename}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitHalt {
+		t.Fatalf("want exit code %d but got %d", exitHalt, code)
+	}
+	for _, want := range []string{"add 2, 3, [5]", "hlt"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Fatalf("want trace to contain %q but got %q", want, stderr.String())
+		}
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, strings.NewReader(""), &stdout, &stderr); code != exitUsage {
+		t.Fatalf("want exit code %d but got %d", exitUsage, code)
+	}
+}
+
+func TestRunCover(t *testing.T) {
+	filename := writeProg(t, "1101,2,3,5,99,0")
+	for _, ext := range []string{".txt", ".html"} {
+		cover := filepath.Join(t.TempDir(), "cover"+ext)
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"run", "-cover", cover, filename}, strings.NewReader(""), &stdout, &stderr)
+		if code != exitHalt {
+			t.Fatalf("want exit code %d but got %d (%s)", exitHalt, code, stderr.String())
+		}
+		buf, err := os.ReadFile(cover)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(buf), "coverage: 2 of 2 instructions") {
+			t.Fatalf("want coverage summary in %s but got\n%s", cover, buf)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package adventofcode2019
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// Coverage counts how often the instruction at each address was executed.
+// A Coverage can be shared by several machines (clones, or multiple runs of
+// a test suite) to accumulate hits.
+type Coverage struct {
+	hits []int
+}
+
+// NewCoverage returns an empty coverage recorder.
+func NewCoverage() *Coverage {
+	return &Coverage{}
+}
+
+// Cover attaches a coverage recorder to the machine, nil detaches. Clones
+// created afterwards record into the same Coverage.
+func (ic *Intcode) Cover(c *Coverage) {
+	ic.cov = c
+}
+
+// hit records one execution of the instruction at addr.
+func (c *Coverage) hit(addr int) {
+	if addr >= len(c.hits) {
+		bigger := make([]int, addr+1, 2*(addr+1))
+		copy(bigger, c.hits)
+		c.hits = bigger
+	}
+	c.hits[addr]++
+}
+
+// Hits returns the number of executions of the instruction at addr.
+func (c *Coverage) Hits(addr int) int {
+	if addr < 0 || addr >= len(c.hits) {
+		return 0
+	}
+	return c.hits[addr]
+}
+
+// CoverageLine is one line of a coverage listing, either an instruction or
+// a data cell that could not be decoded.
+type CoverageLine struct {
+	Instruction
+	Data  bool // true if memory cell is not a decodable instruction
+	Value int  // raw value for data cells
+	Hits  int
+}
+
+// Lines returns a listing of the original program of ic annotated with hit
+// counts. Executed addresses are always decoded as instructions, the gaps
+// in between are disassembled by linear sweep, so data may show up as
+// unexecuted instructions.
+func (c *Coverage) Lines(ic *Intcode) []CoverageLine {
+	prog := ic.Clone()
+	var lines []CoverageLine
+	for addr := 0; addr < len(prog.mem); {
+		in, ok := prog.Decode(addr)
+		// never swallow an executed instruction as parameter of a guess
+		if ok && c.Hits(addr) == 0 {
+			for n := 1; n < in.Len(); n++ {
+				if c.Hits(addr+n) > 0 {
+					ok = false
+					break
+				}
+			}
+		}
+		if !ok {
+			lines = append(lines, CoverageLine{
+				Instruction: Instruction{Addr: addr},
+				Data:        true,
+				Value:       prog.mem[addr],
+				Hits:        c.Hits(addr),
+			})
+			addr++
+			continue
+		}
+		lines = append(lines, CoverageLine{Instruction: in, Hits: c.Hits(addr)})
+		addr += in.Len()
+	}
+	return lines
+}
+
+// CoverageSummary returns the number of executed and total instructions of a
+// listing.
+func CoverageSummary(lines []CoverageLine) (executed, total int) {
+	for _, l := range lines {
+		if l.Data {
+			continue
+		}
+		total++
+		if l.Hits > 0 {
+			executed++
+		}
+	}
+	return
+}
+
+// coveragePercent returns executed/total in percent.
+func coveragePercent(executed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(executed) / float64(total)
+}
+
+// WriteText writes a plain text coverage listing of ic to w.
+func (c *Coverage) WriteText(w io.Writer, ic *Intcode) error {
+	lines := c.Lines(ic)
+	for _, l := range lines {
+		hits := "-"
+		if l.Hits > 0 {
+			hits = fmt.Sprint(l.Hits)
+		}
+		var err error
+		if l.Data {
+			_, err = fmt.Fprintf(w, "%6d %10s  .data %d\n", l.Addr, hits, l.Value)
+		} else {
+			_, err = fmt.Fprintf(w, "%6d %10s  %s\n", l.Addr, hits, l.Instruction)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	executed, total := CoverageSummary(lines)
+	_, err := fmt.Fprintf(w, "coverage: %d of %d instructions (%.1f%%)\n",
+		executed, total, coveragePercent(executed, total))
+	return err
+}
+
+var coverageHTML = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Intcode coverage</title>
+<style>
+body { font-family: monospace; }
+td { padding: 0 1em; }
+td.n { text-align: right; }
+tr.hit { background: #cfc; }
+tr.miss { background: #fcc; }
+tr.data { color: #888; }
+</style>
+</head>
+<body>
+<p>coverage: {{.Executed}} of {{.Total}} instructions ({{printf "%.1f" .Percent}}%)</p>
+<table>
+<tr><th>addr</th><th>hits</th><th>instruction</th></tr>
+{{range .Lines}}{{if .Data}}<tr class="data"><td class="n">{{.Addr}}</td><td class="n">{{if .Hits}}{{.Hits}}{{end}}</td><td>.data {{.Value}}</td></tr>
+{{else}}<tr class="{{if .Hits}}hit{{else}}miss{{end}}"><td class="n">{{.Addr}}</td><td class="n">{{.Hits}}</td><td>{{.Instruction}}</td></tr>
+{{end}}{{end}}</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes a coverage listing of ic as HTML page to w.
+func (c *Coverage) WriteHTML(w io.Writer, ic *Intcode) error {
+	lines := c.Lines(ic)
+	executed, total := CoverageSummary(lines)
+	return coverageHTML.Execute(w, struct {
+		Lines           []CoverageLine
+		Executed, Total int
+		Percent         float64
+	}{lines, executed, total, coveragePercent(executed, total)})
+}
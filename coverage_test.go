@@ -0,0 +1,95 @@
+package adventofcode2019
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCoverageBranch(t *testing.T) {
+	// output 1 if input is 8, skipping the other branch
+	prog := []byte("3,20,1008,20,8,21,1005,21,14,104,0,1105,1,16,104,1,99,0,0,0,0,0")
+	ic, err := NewIntcode(prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := NewCoverage()
+	ic.Cover(cov)
+	if _, err := ic.Run(8); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []int{0, 2, 6, 14, 16} {
+		if cov.Hits(addr) != 1 {
+			t.Fatalf("address %d: want 1 hit but got %d", addr, cov.Hits(addr))
+		}
+	}
+	for _, addr := range []int{9, 11} {
+		if cov.Hits(addr) != 0 {
+			t.Fatalf("address %d: want 0 hits but got %d", addr, cov.Hits(addr))
+		}
+	}
+
+	// second input of the suite takes the other branch
+	ic.Reset()
+	if _, err := ic.Run(7); err != nil {
+		t.Fatal(err)
+	}
+	executed, total := CoverageSummary(cov.Lines(ic))
+	if executed != total {
+		t.Fatalf("want full coverage but got %d of %d", executed, total)
+	}
+	if cov.Hits(0) != 2 {
+		t.Fatalf("want 2 hits for address 0 but got %d", cov.Hits(0))
+	}
+}
+
+func TestCoverageWrite(t *testing.T) {
+	ic, err := NewIntcode([]byte("1101,2,3,5,99,0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := NewCoverage()
+	ic.Cover(cov)
+	if _, err := ic.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var text bytes.Buffer
+	if err := cov.WriteText(&text, ic); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"add 2, 3, [5]", ".data 0", "coverage: 2 of 2 instructions (100.0%)"} {
+		if !strings.Contains(text.String(), want) {
+			t.Fatalf("want listing to contain %q but got\n%s", want, text.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := cov.WriteHTML(&html, ic); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), `<tr class="hit">`) {
+		t.Fatalf("want hit rows in HTML but got\n%s", html.String())
+	}
+}
+
+func TestCoverageDay21(t *testing.T) {
+	ic, err := NewIntcode(fileFromFilename(t, filename, 21))
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverage := func(springscript string) int {
+		cov := NewCoverage()
+		ic.Reset()
+		ic.Cover(cov)
+		executeSpringdroid(ic, springscript)
+		executed, _ := CoverageSummary(cov.Lines(ic))
+		return executed
+	}
+	walk := coverage("WALK\n")
+	jump := coverage("NOT A J\nNOT B T\nOR T J\nNOT C T\nOR T J\nAND D J\nWALK\n")
+	if walk == 0 || jump <= walk {
+		t.Fatalf("want a real springscript to cover more than an empty one, got %d and %d",
+			jump, walk)
+	}
+}
@@ -1,56 +1,138 @@
 package adventofcode2019
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 )
 
+// Module is the fuel requirement of one module, for auditing.
+type Module struct {
+	Line  int    // line number in the input
+	Mass  uint   // mass of the module
+	Fuel  uint   // fuel for the mass of the module
+	Chain []uint // fuel for the fuel, each step for the previous one
+}
+
+// Total returns the fuel for the module including fuel for fuel.
+func (a Module) Total() uint {
+	sum := a.Fuel
+	for _, f := range a.Chain {
+		sum += f
+	}
+	return sum
+}
+
+// fuel computes required fuel for given mass, masses below 9 need none.
+func fuel(mass uint) uint {
+	if mass < 9 {
+		return 0
+	}
+	return mass/3 - 2
+}
+
+// completeFuel computes required fuel for given mass, including fuel for
+// fuel.
+func completeFuel(mass uint) (sum uint) {
+	for f := fuel(mass); f > 0; f = fuel(f) {
+		sum += f
+	}
+	return
+}
+
 // Day01 returns sum of fuel for all modules
 func Day01(input []byte, part1 bool) (uint, error) {
-	// fuel computes required fuel for given mass
-	fuel := func(mass uint) int {
-		return int(mass)/3 - 2
-	}
+	return Day01Reader(bytes.NewReader(input), part1)
+}
 
-	// completeFuel computes required fuel for given mass, including fuel for fuel.
-	// Loop terminates when f <= 0 (fuel can be negative for small masses).
-	completeFuel := func(mass uint) (sum uint) {
-		for f := fuel(mass); f > 0; f = fuel(uint(f)) {
-			sum += uint(f)
+// Day01Reader is Day01 for input that is read in chunks, e.g. from stdin.
+func Day01Reader(r io.Reader, part1 bool) (uint, error) {
+	var sum uint
+	err := scanMasses(r, func(_ int, mass uint) {
+		if part1 {
+			sum += fuel(mass)
+		} else {
+			sum += completeFuel(mass)
+		}
+	})
+	return sum, err
+}
+
+// Day01Modules returns the fuel breakdown of every module. Part 1 is the sum
+// of Fuel, part 2 the sum of Total.
+func Day01Modules(r io.Reader) ([]Module, error) {
+	var modules []Module
+	err := scanMasses(r, func(line int, mass uint) {
+		m := Module{Line: line, Mass: mass, Fuel: fuel(mass)}
+		for f := fuel(m.Fuel); f > 0; f = fuel(f) {
+			m.Chain = append(m.Chain, f)
+		}
+		modules = append(modules, m)
+	})
+	return modules, err
+}
+
+// scanMasses reads r in chunks and calls fn for each mass.
+func scanMasses(r io.Reader, fn func(line int, mass uint)) error {
+	var s massScanner
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if ferr := s.feed(buf[:n], fn); ferr != nil {
+			return ferr
+		}
+		if errors.Is(err, io.EOF) {
+			s.end(fn)
+			return nil
+		}
+		if err != nil {
+			return err
 		}
-		return
 	}
+}
+
+// massScanner parses one mass per line from input fed in pieces. Blank
+// lines, spaces, tabs and carriage returns around a mass are ignored, the
+// last line does not need a newline.
+type massScanner struct {
+	line   int  // 0-based, current line
+	mass   uint // digits of the current line so far
+	digits bool // current line has digits
+	after  bool // whitespace after the digits
+}
 
-	sum := uint(0)
-	num := 0
-	hasDigits := false
+// maxMass keeps mass*10+9 from overflowing.
+const maxMass = (^uint(0) - 9) / 10
 
+func (a *massScanner) feed(input []byte, fn func(line int, mass uint)) error {
 	for _, b := range input {
-		if b >= '0' && b <= '9' {
-			num = num*10 + int(b-'0')
-			hasDigits = true
-		} else if b == '\n' {
-			if hasDigits {
-				mass := uint(num)
-				if part1 {
-					f := fuel(mass)
-					if f > 0 {
-						sum += uint(f)
-					}
-				} else {
-					sum += completeFuel(mass)
-				}
-				num = 0
-				hasDigits = false
+		switch {
+		case b >= '0' && b <= '9':
+			if a.after {
+				return fmt.Errorf("line %d: want one mass per line", a.line+1)
 			}
-		} else {
-			return 0, fmt.Errorf("unexpected byte: %q", b)
+			if a.mass > maxMass {
+				return fmt.Errorf("line %d: mass too large", a.line+1)
+			}
+			a.mass = a.mass*10 + uint(b-'0')
+			a.digits = true
+		case b == '\n':
+			a.end(fn)
+			a.line++
+		case b == ' ' || b == '\t' || b == '\r':
+			a.after = a.digits
+		default:
+			return fmt.Errorf("line %d: unexpected %q", a.line+1, b)
 		}
 	}
+	return nil
+}
 
-	// .editorconfig guarantees insert_final_newline, so error if digits remain
-	if hasDigits {
-		return 0, fmt.Errorf("input does not end with newline")
+// end finishes the current line.
+func (a *massScanner) end(fn func(line int, mass uint)) {
+	if a.digits {
+		fn(a.line+1, a.mass)
 	}
-
-	return sum, nil
+	a.mass, a.digits, a.after = 0, false, false
 }
@@ -1,8 +1,12 @@
 package adventofcode2019
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestDay01Part1Examples(t *testing.T) {
@@ -67,3 +71,76 @@ func BenchmarkDay01Part1(b *testing.B) {
 func BenchmarkDay01Part2(b *testing.B) {
 	benchSolver(b, 1, false, Day01)
 }
+
+func TestDay01Reader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"LF", "12\n1969\n"},
+		{"CRLF", "12\r\n1969\r\n"},
+		{"no final newline", "12\n1969"},
+		{"whitespace", "  12 \t\n\n1969  \r\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Day01Reader(strings.NewReader(tt.input), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := uint(2 + 966); want != got {
+				t.Fatalf("want %d but got %d", want, got)
+			}
+		})
+	}
+}
+
+// TestDay01ReaderChunks feeds the input one byte at a time so that masses
+// span reads.
+func TestDay01ReaderChunks(t *testing.T) {
+	buf := fileFromFilename(t, filename, 1)
+	got, err := Day01Reader(iotest.OneByteReader(bytes.NewReader(buf)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint(3231195); want != got {
+		t.Fatalf("want %d but got %d", want, got)
+	}
+}
+
+func TestDay01Errors(t *testing.T) {
+	cases := []errorCase[string]{
+		{"letter", "12\n14\n1x\n", true, `line 3: unexpected 'x'`},
+		{"negative", "12\r\n-14\r\n", true, `line 2: unexpected '-'`},
+		{"two masses", "12 14\n", true, "line 1: want one mass per line"},
+		{"overflow", "12\n99999999999999999999999\n", true, "line 2: mass too large"},
+	}
+	testErrors(t, fromString(Day01), cases)
+	testErrors(t, func(input string, part1 bool) (uint, error) {
+		return Day01Reader(strings.NewReader(input), part1)
+	}, cases)
+}
+
+func TestDay01Modules(t *testing.T) {
+	modules, err := Day01Modules(strings.NewReader("12\n\n1969\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Module{
+		{Line: 1, Mass: 12, Fuel: 2},
+		{Line: 3, Mass: 1969, Fuel: 654, Chain: []uint{216, 70, 21, 5}},
+	}
+	if !reflect.DeepEqual(want, modules) {
+		t.Fatalf("want %+v but got %+v", want, modules)
+	}
+	if got := modules[1].Total(); got != 966 {
+		t.Fatalf("want total 966 but got %d", got)
+	}
+}
+
+func BenchmarkDay01Reader(b *testing.B) {
+	buf := fileFromFilename(b, filename, 1)
+	for b.Loop() {
+		_, _ = Day01Reader(bytes.NewReader(buf), false)
+	}
+}
@@ -1,10 +1,8 @@
 package adventofcode2019
 
-import "errors"
-
 // Day02 solves the 1202 Program Alarm puzzle
 func Day02(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
@@ -19,19 +17,9 @@ func Day02(program []byte, part1 bool) (uint, error) {
 	}
 
 	// Part 2: Find noun and verb that produce output 19690720
-	for noun := range 100 {
-		for verb := range 100 {
-			ic.Reset()
-			ic.SetMem(1, noun)
-			ic.SetMem(2, verb)
-			if _, err := ic.Run(); err != nil {
-				continue
-			}
-			if ic.Mem(0) == 19690720 {
-				return uint(100*noun + verb), nil
-			}
-		}
+	values, err := GoalSeek(ic, []Param{{1, 0, 99}, {2, 0, 99}}, 0, 19690720)
+	if err != nil {
+		return 0, err
 	}
-
-	return 0, errors.New("no solution found")
+	return uint(100*values[0] + values[1]), nil
 }
@@ -1,8 +1,14 @@
 package adventofcode2019
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"image/color"
+	"io"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -28,74 +34,78 @@ type segment struct {
 	horizontal     bool // true if horizontal (y1==y2)
 }
 
-// Day03 computes the minimal manhattan distance or minimal combined steps of two crossing wires
-func Day03(wires []string, part1 bool) uint {
-	// Parse both wires into segments
-	segs1 := parseWireSegments(wires[0])
-	segs2 := parseWireSegments(wires[1])
-
-	minResult := math.MaxInt32
-
-	// Check all segment pairs for intersections
-	for _, s1 := range segs1 {
-		for _, s2 := range segs2 {
-			// Only horizontal-vertical pairs can intersect
-			if s1.horizontal == s2.horizontal {
-				continue
-			}
-
-			var hSeg, vSeg segment
-			if s1.horizontal {
-				hSeg, vSeg = s1, s2
-			} else {
-				hSeg, vSeg = s2, s1
-			}
-
-			// Check if they intersect
-			// hSeg: horizontal line at y=hSeg.y1, x from min(x1,x2) to max(x1,x2)
-			// vSeg: vertical line at x=vSeg.x1, y from min(y1,y2) to max(y1,y2)
-			hMinX, hMaxX := hSeg.x1, hSeg.x2
-			if hMinX > hMaxX {
-				hMinX, hMaxX = hMaxX, hMinX
-			}
-			vMinY, vMaxY := vSeg.y1, vSeg.y2
-			if vMinY > vMaxY {
-				vMinY, vMaxY = vMaxY, vMinY
-			}
-
-			// Intersection point would be (vSeg.x1, hSeg.y1)
-			ix, iy := vSeg.x1, hSeg.y1
+// Day03 computes the minimal manhattan distance or minimal combined steps of
+// crossing wires, see Wiring.
+func Day03(wires []string, part1 bool) (uint, error) {
+	w, err := NewWiring(wires)
+	if err != nil {
+		return 0, err
+	}
+	crossings := w.Crossings()
+	if len(crossings) == 0 {
+		return 0, errors.New("wires do not cross")
+	}
+	if part1 {
+		// sorted by distance
+		return uint(crossings[0].Distance), nil
+	}
+	delay := math.MaxInt
+	for _, c := range crossings {
+		delay = min(delay, c.Delay())
+	}
+	return uint(delay), nil
+}
 
-			// Check if intersection point is within both segments
-			if ix >= hMinX && ix <= hMaxX && iy >= vMinY && iy <= vMaxY {
-				// Skip origin
-				if ix == 0 && iy == 0 {
-					continue
-				}
+// apart reports whether the bounding boxes of a and b do not touch.
+func apart(a, b segment) bool {
+	return max(a.x1, a.x2) < min(b.x1, b.x2) || max(b.x1, b.x2) < min(a.x1, a.x2) ||
+		max(a.y1, a.y2) < min(b.y1, b.y2) || max(b.y1, b.y2) < min(a.y1, a.y2)
+}
 
-				if part1 {
-					dist := abs03(ix) + abs03(iy)
-					if dist < minResult {
-						minResult = dist
-					}
-				} else {
-					// Calculate steps to intersection for each wire
-					steps1 := s1.steps + abs03(ix-s1.x1) + abs03(iy-s1.y1)
-					steps2 := s2.steps + abs03(ix-s2.x1) + abs03(iy-s2.y1)
-					totalSteps := steps1 + steps2
-					if totalSteps < minResult {
-						minResult = totalSteps
-					}
-				}
-			}
+// overlap returns the points that segments a and b share as a segment from
+// x1, y1 to x2, y2 with x1 <= x2 and y1 <= y2. Perpendicular segments share
+// at most one point, collinear segments every point of their overlap.
+func overlap(a, b segment) (segment, bool) {
+	if a.horizontal != b.horizontal {
+		h, v := a, b
+		if !a.horizontal {
+			h, v = b, a
+		}
+		// Intersection point would be (v.x1, h.y1)
+		x, y := v.x1, h.y1
+		if between(x, h.x1, h.x2) && between(y, v.y1, v.y2) {
+			return segment{x1: x, y1: y, x2: x, y2: y}, true
+		}
+		return segment{}, false
+	}
+	if a.horizontal {
+		if a.y1 != b.y1 {
+			return segment{}, false
 		}
+		from := max(min(a.x1, a.x2), min(b.x1, b.x2))
+		to := min(max(a.x1, a.x2), max(b.x1, b.x2))
+		return segment{x1: from, y1: a.y1, x2: to, y2: a.y1, horizontal: true}, from <= to
 	}
+	if a.x1 != b.x1 {
+		return segment{}, false
+	}
+	from := max(min(a.y1, a.y2), min(b.y1, b.y2))
+	to := min(max(a.y1, a.y2), max(b.y1, b.y2))
+	return segment{x1: a.x1, y1: from, x2: a.x1, y2: to}, from <= to
+}
+
+// between reports whether n lies between a and b, inclusive, in any order.
+func between(n, a, b int) bool {
+	return min(a, b) <= n && n <= max(a, b)
+}
 
-	return uint(minResult)
+// stepsTo returns the steps along the wire to reach point x, y on s.
+func (s segment) stepsTo(x, y int) int {
+	return s.steps + abs03(x-s.x1) + abs03(y-s.y1)
 }
 
 // parseWireSegments parses a wire string into segments using inline parsing
-func parseWireSegments(wire string) []segment {
+func parseWireSegments(wire string) ([]segment, error) {
 	// Estimate capacity: roughly one segment per 5 chars
 	segs := make([]segment, 0, len(wire)/5+1)
 	x, y, steps := 0, 0, 0
@@ -103,7 +113,11 @@ func parseWireSegments(wire string) []segment {
 	i := 0
 	for i < len(wire) {
 		// Parse direction
+		from := i
 		dir := wire[i]
+		if dir != 'R' && dir != 'L' && dir != 'U' && dir != 'D' {
+			return nil, fmt.Errorf("illegal direction %q at column %d", dir, from+1)
+		}
 		i++
 
 		// Parse number
@@ -112,10 +126,15 @@ func parseWireSegments(wire string) []segment {
 			num = num*10 + int(wire[i]-'0')
 			i++
 		}
+		if i == from+1 {
+			return nil, fmt.Errorf("missing length at column %d", i+1)
+		}
 
 		// Skip comma
 		if i < len(wire) && wire[i] == ',' {
 			i++
+		} else if i < len(wire) {
+			return nil, fmt.Errorf("want ',' but got %q at column %d", wire[i], i+1)
 		}
 
 		// Create segment
@@ -142,7 +161,7 @@ func parseWireSegments(wire string) []segment {
 		steps += num
 	}
 
-	return segs
+	return segs, nil
 }
 
 func abs03(n int) int {
@@ -154,7 +173,8 @@ func abs03(n int) int {
 
 // day3Part1 computes the minimal manhattan distance of two crossing wires
 func day3Part1(wires []string) (int, error) {
-	return int(Day03(wires, true)), nil
+	n, err := Day03(wires, true)
+	return int(n), err
 }
 
 // parse splits a path such as U32 into a direction North and a length 32
@@ -208,5 +228,215 @@ func size(wiring string) (int, int, error) {
 
 // day3Part2 computes the minimal combined steps for intersections
 func day3Part2(wires []string) (int, error) {
-	return int(Day03(wires, false)), nil
+	n, err := Day03(wires, false)
+	return int(n), err
+}
+
+// Wiring is a set of wires that all start at the central port.
+type Wiring struct {
+	wires [][]segment
+}
+
+// Crossing is a point, other than the central port, where two or more wires
+// meet.
+type Crossing struct {
+	X, Y     int
+	Distance int   // Manhattan distance to the central port
+	Steps    []int // per wire, fewest steps to reach the crossing, 0 if it does not pass
+}
+
+// Delay returns the combined steps of all wires to reach the crossing.
+func (a Crossing) Delay() int {
+	var sum int
+	for _, n := range a.Steps {
+		sum += n
+	}
+	return sum
+}
+
+// Wires returns the number of wires that meet at the crossing.
+func (a Crossing) Wires() int {
+	var n int
+	for _, steps := range a.Steps {
+		if steps > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// NewWiring parses one wire per line.
+func NewWiring(lines []string) (*Wiring, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("no wires")
+	}
+	w := &Wiring{wires: make([][]segment, len(lines))}
+	for i, line := range lines {
+		segs, err := parseWireSegments(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		w.wires[i] = segs
+	}
+	return w, nil
+}
+
+// Crossings returns all points where different wires meet, including every
+// point of collinear overlaps, ordered by distance, then by x and y. A wire
+// crossing itself does not count.
+func (a *Wiring) Crossings() []Crossing {
+	type point struct{ x, y int }
+	crossings := make(map[point][]int)
+	// visit records steps for wire i, keeping the fewest
+	visit := func(p point, i, steps int) {
+		s, ok := crossings[p]
+		if !ok {
+			s = make([]int, len(a.wires))
+			crossings[p] = s
+		}
+		if s[i] == 0 || steps < s[i] {
+			s[i] = steps
+		}
+	}
+	for i := range a.wires {
+		for j := i + 1; j < len(a.wires); j++ {
+			for _, s1 := range a.wires[i] {
+				for _, s2 := range a.wires[j] {
+					if apart(s1, s2) {
+						continue
+					}
+					o, ok := overlap(s1, s2)
+					if !ok {
+						continue
+					}
+					for x := o.x1; x <= o.x2; x++ {
+						for y := o.y1; y <= o.y2; y++ {
+							if x == 0 && y == 0 {
+								continue
+							}
+							p := point{x, y}
+							visit(p, i, s1.stepsTo(x, y))
+							visit(p, j, s2.stepsTo(x, y))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	list := make([]Crossing, 0, len(crossings))
+	for p, steps := range crossings {
+		list = append(list, Crossing{X: p.x, Y: p.y, Distance: abs03(p.x) + abs03(p.y), Steps: steps})
+	}
+	slices.SortFunc(list, func(a, b Crossing) int {
+		if a.Distance != b.Distance {
+			return a.Distance - b.Distance
+		}
+		if a.X != b.X {
+			return a.X - b.X
+		}
+		return a.Y - b.Y
+	})
+	return list
+}
+
+// maxWiringCells is the longer side of a wiring frame, wires spanning more
+// units are scaled down.
+const maxWiringCells = 400
+
+// day03Frames draws the wires, up is up, with the central port and the
+// crossings.
+func day03Frames(input []byte) ([]*Frame, error) {
+	lines, err := readLines(bytes.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWiring(lines)
+	if err != nil {
+		return nil, err
+	}
+	return []*Frame{w.frame()}, nil
+}
+
+// frame draws wire i in ink 1 + i modulo the wire colors, crossings and the
+// central port in the last two inks.
+func (a *Wiring) frame() *Frame {
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	for _, segs := range a.wires {
+		for _, s := range segs {
+			minX, maxX = min(minX, s.x2), max(maxX, s.x2)
+			minY, maxY = min(minY, s.y2), max(maxY, s.y2)
+		}
+	}
+	// units per cell, rounded up
+	scale := (max(maxX-minX, maxY-minY) + maxWiringCells) / maxWiringCells
+	cell := func(x, y int) (int, int) {
+		return (x - minX) / scale, (maxY - y) / scale
+	}
+
+	palette := []Ink{{' ', black}}
+	for i, c := range wireColors {
+		palette = append(palette, Ink{rune('1' + i), c})
+	}
+	palette = append(palette, Ink{'X', white}, Ink{'o', white})
+	crossing, port := uint8(len(palette)-2), uint8(len(palette)-1)
+
+	width, height := cell(maxX, minY)
+	f := NewFrame(width+1, height+1, palette)
+	for i, segs := range a.wires {
+		ink := uint8(1 + i%len(wireColors))
+		for _, s := range segs {
+			for x := min(s.x1, s.x2); x <= max(s.x1, s.x2); x++ {
+				for y := min(s.y1, s.y2); y <= max(s.y1, s.y2); y++ {
+					cx, cy := cell(x, y)
+					f.Set(cx, cy, ink)
+				}
+			}
+		}
+	}
+	for _, c := range a.Crossings() {
+		x, y := cell(c.X, c.Y)
+		f.Set(x, y, crossing)
+	}
+	x, y := cell(0, 0)
+	f.Set(x, y, port)
+	return f
+}
+
+// wireColors are the strokes of wires in SVG, repeating for more wires.
+var wireColors = []color.RGBA{red, blue, green, yellow, gray}
+
+// WriteSVG draws the wires as polylines, up is up, the central port as a
+// black square and crossings as black dots.
+func (a *Wiring) WriteSVG(w io.Writer) error {
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	for _, segs := range a.wires {
+		for _, s := range segs {
+			minX, maxX = min(minX, s.x2), max(maxX, s.x2)
+			minY, maxY = min(minY, s.y2), max(maxY, s.y2)
+		}
+	}
+	width, height := maxX-minX, maxY-minY
+	// margin and marks scale with the drawing, lines have a fixed width
+	r := max(width/100, height/100, 1)
+	margin := 2*r + 1
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%d %d %d %d\">\n",
+		minX-margin, -maxY-margin, width+2*margin, height+2*margin)
+	for i, segs := range a.wires {
+		c := wireColors[i%len(wireColors)]
+		fmt.Fprintf(bw, "<polyline fill=\"none\" stroke=\"#%02x%02x%02x\" stroke-width=\"1\" "+
+			"vector-effect=\"non-scaling-stroke\" points=\"0,0", c.R, c.G, c.B)
+		for _, s := range segs {
+			fmt.Fprintf(bw, " %d,%d", s.x2, -s.y2)
+		}
+		bw.WriteString("\"/>\n")
+	}
+	for _, c := range a.Crossings() {
+		fmt.Fprintf(bw, "<circle cx=\"%d\" cy=\"%d\" r=\"%d\"/>\n", c.X, -c.Y, r)
+	}
+	fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n", -r, -r, 2*r, 2*r)
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
 }
@@ -1,7 +1,11 @@
 package adventofcode2019
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -73,7 +77,7 @@ func TestDay03Part1(t *testing.T) {
 func BenchmarkDay03Part1(b *testing.B) {
 	lines := testLinesFromFilename(b, filename(3))
 	for b.Loop() {
-		_ = Day03(lines, true)
+		_, _ = Day03(lines, true)
 	}
 }
 
@@ -101,6 +105,100 @@ func TestDay03Part2(t *testing.T) {
 func BenchmarkDay03Part2(b *testing.B) {
 	lines := testLinesFromFilename(b, filename(3))
 	for b.Loop() {
-		_ = Day03(lines, false)
+		_, _ = Day03(lines, false)
+	}
+}
+
+func TestDay03Errors(t *testing.T) {
+	testErrors(t, Day03, []errorCase[[]string]{
+		{"no wires", nil, true, "no wires"},
+		{"one wire", []string{"R8,U5"}, true, "wires do not cross"},
+		{"direction", []string{"R8,U5", "U7,X6"}, true, "line 2: illegal direction 'X' at column 4"},
+		{"length", []string{"R8,U", "U7,R6"}, true, "line 1: missing length at column 5"},
+		{"separator", []string{"R8;U5", "U7,R6"}, true, "line 1: want ',' but got ';' at column 3"},
+		{"parallel", []string{"R8", "U7"}, true, "wires do not cross"},
+	})
+}
+
+func TestDay03Collinear(t *testing.T) {
+	wires := []string{"R10", "L2,R8"}
+	for _, tt := range []struct {
+		part1 bool
+		want  uint
+	}{{true, 1}, {false, 6}} {
+		got, err := Day03(wires, tt.part1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != got {
+			t.Fatalf("part1=%t: want %d but got %d", tt.part1, tt.want, got)
+		}
+	}
+}
+
+func TestWiringCrossings(t *testing.T) {
+	w, err := NewWiring([]string{"R8,U5,L5,D3", "U7,R6,D4,L4", "U3,R3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Crossing{
+		{0, 1, 1, []int{0, 1, 1}},
+		{0, 2, 2, []int{0, 2, 2}},
+		{0, 3, 3, []int{0, 3, 3}},
+		{2, 3, 5, []int{0, 21, 5}},
+		{3, 3, 6, []int{20, 20, 6}},
+		{6, 5, 11, []int{15, 15, 0}},
+	}
+	got := w.Crossings()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+	if n := got[4].Wires(); n != 3 {
+		t.Fatalf("want 3 wires at 3,3 but got %d", n)
+	}
+	if d := got[5].Delay(); d != 30 {
+		t.Fatalf("want delay 30 at 6,5 but got %d", d)
+	}
+}
+
+func TestWiringErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		wires []string
+		want  string
+	}{
+		{"no wires", nil, "no wires"},
+		{"direction", []string{"R8,U5", "U7", "U7,X6"}, "line 3: illegal direction 'X' at column 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWiring(tt.wires)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("want error %q but got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWiringSVG(t *testing.T) {
+	w, err := NewWiring(day3Examples[0].in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := w.WriteSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+		t.Fatalf("want well-formed XML: %v", err)
+	}
+	for elem, want := range map[string]int{"<polyline ": 2, "<circle ": len(w.Crossings()), "<rect ": 1} {
+		if got := strings.Count(svg, elem); want != got {
+			t.Fatalf("want %d %s elements but got %d", want, elem, got)
+		}
+	}
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="-5 -122 248 157">`) {
+		t.Fatalf("unexpected header %q", svg[:strings.IndexByte(svg, '\n')])
 	}
 }
@@ -1,20 +1,14 @@
 package adventofcode2019
 
-// criteria is a function that tests an integer with its digits.
-type criteria func(int, []byte) bool
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
-func critSixDigits(n int, digits []byte) bool {
-	return len(digits) == 6
-}
-
-func critTwoOrMoreAdjacent(n int, digits []byte) bool {
-	for i := 1; i < len(digits); i++ {
-		if digits[i-1] == digits[i] {
-			return true
-		}
-	}
-	return false
-}
+// Criterion tests a password n, digits holds its decimal digits 0-9, most
+// significant first.
+type Criterion func(n int, digits []byte) bool
 
 func critIncreasing(n int, digits []byte) bool {
 	for i := 1; i < len(digits); i++ {
@@ -25,29 +19,29 @@ func critIncreasing(n int, digits []byte) bool {
 	return true
 }
 
-func critExactlyTwoAdjacent(n int, digits []byte) bool {
-	// Start of digits: aa != a
-	if (digits[0] == digits[1]) && (digits[1] != digits[2]) {
-		return true
+// critGroup returns a criterion for a group of equal adjacent digits with a
+// size from lo to hi, hi 0 meaning any size of at least lo.
+func critGroup(lo, hi int) Criterion {
+	fits := func(size int) bool {
+		return size >= lo && (hi == 0 || size <= hi)
 	}
-
-	// Middle: != aa !=
-	for i := 2; i < 5; i++ {
-		if (digits[i-2] != digits[i-1]) &&
-			(digits[i-1] == digits[i]) &&
-			(digits[i] != digits[i+1]) {
-			return true
+	return func(_ int, digits []byte) bool {
+		size := 1
+		for i := 1; i < len(digits); i++ {
+			if digits[i-1] == digits[i] {
+				size++
+				continue
+			}
+			if fits(size) {
+				return true
+			}
+			size = 1
 		}
+		return fits(size)
 	}
-
-	// End of digits: != aa
-	if (digits[3] != digits[4]) && (digits[4] == digits[5]) {
-		return true
-	}
-	return false
 }
 
-func meetsCriteria(n int, digits []byte, crits []criteria) bool {
+func meetsCriteria(n int, digits []byte, crits []Criterion) bool {
 	for _, f := range crits {
 		if !f(n, digits) {
 			return false
@@ -56,45 +50,185 @@ func meetsCriteria(n int, digits []byte, crits []criteria) bool {
 	return true
 }
 
-// Day04 returns number of passwords that meet all criteria
-func Day04(part1 bool) uint {
-	const lower, upper = 136818, 685979
-	count := uint(0)
-	digits := make([]byte, 6)
-
-	for n := lower; n < upper; n++ {
-		digitsInto(n, digits)
-
-		// Check increasing first (fastest rejection)
-		increasing := true
-		for i := 1; i < 6; i++ {
-			if digits[i-1] > digits[i] {
-				increasing = false
-				break
+// Day04Puzzle is the inclusive range of candidate passwords.
+type Day04Puzzle struct {
+	Lower, Upper int
+}
+
+// NewDay04 parses a range such as 136818-685979 from a single line.
+func NewDay04(lines []string) (Day04Puzzle, error) {
+	if len(lines) != 1 {
+		return Day04Puzzle{}, fmt.Errorf("want 1 line but got %d", len(lines))
+	}
+	line := strings.TrimSpace(lines[0])
+	lo, hi, ok := strings.Cut(line, "-")
+	if !ok {
+		return Day04Puzzle{}, fmt.Errorf("want range lower-upper but got %q", line)
+	}
+	lower, err := strconv.Atoi(lo)
+	if err != nil || lower < 0 {
+		return Day04Puzzle{}, fmt.Errorf("want number for lower bound but got %q", lo)
+	}
+	upper, err := strconv.Atoi(hi)
+	if err != nil || upper < 0 {
+		return Day04Puzzle{}, fmt.Errorf("want number for upper bound but got %q", hi)
+	}
+	if lower > upper {
+		return Day04Puzzle{}, fmt.Errorf("lower bound %d is above upper bound %d", lower, upper)
+	}
+	return Day04Puzzle{lower, upper}, nil
+}
+
+// countPasswords returns the number of passwords from lower to upper,
+// inclusive, that meet all criteria.
+func countPasswords(lower, upper int, crits []Criterion) uint {
+	var count uint
+	buf := make([]byte, ndigits(lower), ndigits(upper))
+	// next is the first number with one more digit, -1 if the range does
+	// not reach it
+	next := -1
+	if len(buf) < cap(buf) {
+		next = 1
+		for range len(buf) {
+			next *= 10
+		}
+	}
+	for n := lower; ; n++ {
+		if n == next {
+			buf = buf[:len(buf)+1]
+			next *= 10
+			if len(buf) == cap(buf) {
+				next = -1
 			}
 		}
-		if !increasing {
-			continue
+		digitsInto(n, buf)
+		if meetsCriteria(n, buf, crits) {
+			count++
 		}
+		if n == upper {
+			return count
+		}
+	}
+}
 
-		if part1 {
-			// Check for any two adjacent digits
-			hasAdjacent := false
-			for i := 1; i < 6; i++ {
-				if digits[i-1] == digits[i] {
-					hasAdjacent = true
-					break
-				}
+// groupRule requires non-decreasing digits and a group of equal adjacent
+// digits with a size from min to max, max 0 meaning any size of at least
+// min. Part 1 is {2, 0}, part 2 {2, 2}.
+type groupRule struct {
+	min, max int
+}
+
+// countGroups returns the number of passwords from lower to upper,
+// inclusive, that satisfy rule. Unlike countPasswords, it counts digit by
+// digit instead of enumerating the range, so ranges of up to 18 digits take
+// microseconds.
+func countGroups(lower, upper int, rule groupRule) uint {
+	n := countGroupsUpTo(upper, rule)
+	if lower > 0 {
+		n -= countGroupsUpTo(lower-1, rule)
+	}
+	return n
+}
+
+// countGroupsUpTo returns the number of passwords from 0 to x that satisfy
+// rule.
+func countGroupsUpTo(x int, rule groupRule) uint {
+	bound := digits(x)
+	// larger groups behave the same as one of size maxSize
+	maxSize := rule.min
+	if rule.max > 0 {
+		maxSize = rule.max + 1
+	}
+	fits := func(size int) bool {
+		return size >= rule.min && (rule.max == 0 || size <= rule.max)
+	}
+
+	// state after the digits left of pos: the last digit, the size of its
+	// group so far, whether an earlier group fits, and whether there was a
+	// digit other than a leading zero
+	type state struct {
+		pos, prev, size int
+		ok, started     bool
+	}
+	// counts of states whose digits are below the prefix of x, so that any
+	// digits may follow
+	memo := make(map[state]uint)
+	var count func(s state, tight bool) uint
+	count = func(s state, tight bool) uint {
+		if s.pos == len(bound) {
+			if !s.started {
+				// 0 is a single digit
+				return b2u(fits(1))
 			}
-			if hasAdjacent {
-				count++
+			return b2u(s.ok || fits(s.size))
+		}
+		if !tight {
+			if n, ok := memo[s]; ok {
+				return n
 			}
-		} else {
-			// Check for exactly two adjacent digits
-			if critExactlyTwoAdjacent(0, digits) {
-				count++
+		}
+		hi := 9
+		if tight {
+			hi = int(bound[s.pos])
+		}
+		var n uint
+		for d := 0; d <= hi; d++ {
+			next := state{pos: s.pos + 1, prev: d, size: 1, ok: s.ok, started: true}
+			switch {
+			case !s.started && d == 0:
+				// leading zero
+				next.started = false
+			case !s.started:
+			case d < s.prev:
+				continue
+			case d == s.prev:
+				next.size = min(s.size+1, maxSize)
+			default:
+				next.ok = s.ok || fits(s.size)
 			}
+			n += count(next, tight && d == hi)
 		}
+		if !tight {
+			memo[s] = n
+		}
+		return n
+	}
+	return count(state{}, true)
+}
+
+func b2u(b bool) uint {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// PasswordRule describes valid passwords. Digits never decrease, and a group
+// of equal adjacent digits has a size from GroupMin to GroupMax, GroupMax 0
+// meaning any size of at least GroupMin.
+type PasswordRule struct {
+	GroupMin, GroupMax int
+	// Criteria are further checks. They need every number of the range to
+	// be tested, which takes milliseconds for six digits.
+	Criteria []Criterion
+}
+
+// Day04 returns number of passwords in range that meet the rules of part 1
+// or part 2. The number of digits follows from the range.
+func Day04(puzzle Day04Puzzle, part1 bool) uint {
+	rule := PasswordRule{GroupMin: 2}
+	if !part1 {
+		rule.GroupMax = 2
+	}
+	return Day04Rule(puzzle, rule)
+}
+
+// Day04Rule returns number of passwords in range that meet rule. Without
+// further criteria it counts digit by digit, else it tests every number.
+func Day04Rule(puzzle Day04Puzzle, rule PasswordRule) uint {
+	if len(rule.Criteria) == 0 {
+		return countGroups(puzzle.Lower, puzzle.Upper, groupRule{rule.GroupMin, rule.GroupMax})
 	}
-	return count
+	crits := append([]Criterion{critIncreasing, critGroup(rule.GroupMin, rule.GroupMax)}, rule.Criteria...)
+	return countPasswords(puzzle.Lower, puzzle.Upper, crits)
 }
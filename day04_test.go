@@ -2,9 +2,45 @@ package adventofcode2019
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"testing"
 )
 
+// critDigits returns a criterion for numbers of exactly n digits.
+func critDigits(n int) Criterion {
+	return func(_ int, digits []byte) bool {
+		return len(digits) == n
+	}
+}
+
+var critSixDigits = critDigits(6)
+
+func critTwoOrMoreAdjacent(n int, digits []byte) bool {
+	for i := 1; i < len(digits); i++ {
+		if digits[i-1] == digits[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// critExactlyTwoAdjacent requires a pair of adjacent digits that is not part
+// of a larger group.
+func critExactlyTwoAdjacent(n int, digits []byte) bool {
+	group := 1
+	for i := 1; i < len(digits); i++ {
+		if digits[i-1] == digits[i] {
+			group++
+			continue
+		}
+		if group == 2 {
+			return true
+		}
+		group = 1
+	}
+	return group == 2
+}
+
 func TestDay04Part1Examples(t *testing.T) {
 	tests := []struct {
 		in  int
@@ -14,7 +50,7 @@ func TestDay04Part1Examples(t *testing.T) {
 		{223450, false},
 		{123789, false},
 	}
-	crits := []criteria{critSixDigits, critIncreasing, critTwoOrMoreAdjacent}
+	crits := []Criterion{critSixDigits, critIncreasing, critTwoOrMoreAdjacent}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("%d", tt.in), func(t *testing.T) {
 			got := meetsCriteria(tt.in, digits(tt.in), crits)
@@ -26,17 +62,11 @@ func TestDay04Part1Examples(t *testing.T) {
 }
 
 func TestDay04Part1(t *testing.T) {
-	want := uint(1919)
-	got := Day04(true)
-	if want != got {
-		t.Fatalf("want %d but got %d", want, got)
-	}
+	testWithParser(t, 4, filename, true, NewDay04, Day04, uint(1919))
 }
 
 func BenchmarkDay04Part1(b *testing.B) {
-	for b.Loop() {
-		_ = Day04(true)
-	}
+	benchWithParser(b, 4, true, NewDay04, Day04)
 }
 
 func TestDay04Part2Examples(t *testing.T) {
@@ -50,7 +80,7 @@ func TestDay04Part2Examples(t *testing.T) {
 		{111223, true},
 		{144456, false},
 	}
-	crits := []criteria{critSixDigits, critIncreasing, critExactlyTwoAdjacent}
+	crits := []Criterion{critSixDigits, critIncreasing, critExactlyTwoAdjacent}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("%d", tt.in), func(t *testing.T) {
 			got := meetsCriteria(tt.in, digits(tt.in), crits)
@@ -62,15 +92,140 @@ func TestDay04Part2Examples(t *testing.T) {
 }
 
 func TestDay04Part2(t *testing.T) {
-	want := uint(1291)
-	got := Day04(false)
-	if want != got {
-		t.Fatalf("want %d but got %d", want, got)
-	}
+	testWithParser(t, 4, filename, false, NewDay04, Day04, uint(1291))
 }
 
 func BenchmarkDay04Part2(b *testing.B) {
+	benchWithParser(b, 4, false, NewDay04, Day04)
+}
+
+func TestDay04Lengths(t *testing.T) {
+	tests := []struct {
+		lower, upper int
+		crits        []Criterion
+		want         uint
+	}{
+		// 0 and 1..9 are increasing but have no pair
+		{0, 99, []Criterion{critIncreasing, critTwoOrMoreAdjacent}, 9},
+		{0, 99, []Criterion{critIncreasing, critExactlyTwoAdjacent}, 9},
+		{0, 999, []Criterion{critIncreasing, critExactlyTwoAdjacent}, 9 + 72},
+		// growing from 3 to 4 digits
+		{990, 1111, []Criterion{critIncreasing, critTwoOrMoreAdjacent}, 2},
+		{990, 1111, []Criterion{critDigits(4)}, 112},
+		{12345678, 12345699, []Criterion{critIncreasing, critExactlyTwoAdjacent}, 2},
+		{5, 5, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d-%d", tt.lower, tt.upper), func(t *testing.T) {
+			got := countPasswords(tt.lower, tt.upper, tt.crits)
+			if tt.want != got {
+				t.Fatalf("want %d but got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDay04Rule(t *testing.T) {
+	puzzle := Day04Puzzle{990, 1111}
+	tests := []struct {
+		name string
+		rule PasswordRule
+		want uint
+	}{
+		// 999, 1111
+		{"group", PasswordRule{GroupMin: 2}, 2},
+		{"four digits", PasswordRule{GroupMin: 2, Criteria: []Criterion{critDigits(4)}}, 1},
+		{"even", PasswordRule{GroupMin: 3, Criteria: []Criterion{func(n int, _ []byte) bool {
+			return n%2 == 0
+		}}}, 0},
+		{"odd", PasswordRule{GroupMin: 3, Criteria: []Criterion{func(n int, _ []byte) bool {
+			return n%2 == 1
+		}}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Day04Rule(puzzle, tt.rule); tt.want != got {
+				t.Fatalf("want %d but got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDay04Errors(t *testing.T) {
+	parse := func(lines []string, _ bool) (Day04Puzzle, error) {
+		return NewDay04(lines)
+	}
+	testErrors(t, parse, []errorCase[[]string]{
+		{"empty", nil, true, "want 1 line but got 0"},
+		{"separator", []string{"136818:685979"}, true, `want range lower-upper but got "136818:685979"`},
+		{"lower", []string{"-1-685979"}, true, `want number for lower bound but got ""`},
+		{"upper", []string{"136818-x"}, true, `want number for upper bound but got "x"`},
+		{"order", []string{"685979-136818"}, true, "lower bound 685979 is above upper bound 136818"},
+	})
+}
+
+// TestDay04CountGroups cross-checks counting digit by digit against
+// enumerating all numbers.
+func TestDay04CountGroups(t *testing.T) {
+	rules := map[groupRule][]Criterion{
+		{2, 0}: {critIncreasing, critTwoOrMoreAdjacent},
+		{2, 2}: {critIncreasing, critExactlyTwoAdjacent},
+		{3, 0}: {critIncreasing, critGroup(3, 0)},
+		{2, 3}: {critIncreasing, critGroup(2, 3)},
+	}
+	ranges := [][2]int{
+		{0, 0}, {0, 9}, {0, 11}, {11, 11}, {12, 110}, {0, 99999},
+		{99, 1000}, {136818, 685979}, {111111, 111111}, {123456, 123456},
+		{5000, 50000}, {777777, 1234567},
+	}
+	rnd := rand.New(rand.NewPCG(4, 2019))
+	for range 20 {
+		lower := rnd.IntN(2000000)
+		ranges = append(ranges, [2]int{lower, lower + rnd.IntN(200000)})
+	}
+	for rule, crits := range rules {
+		for _, r := range ranges {
+			want := countPasswords(r[0], r[1], crits)
+			got := countGroups(r[0], r[1], rule)
+			if want != got {
+				t.Fatalf("rule %v, range %d-%d: want %d but got %d", rule, r[0], r[1], want, got)
+			}
+		}
+	}
+}
+
+// TestDay04CountGroupsWide checks ranges too wide to enumerate. A number with
+// non-decreasing digits 1-9 is a multiset of its digits, and it has no group
+// of two or more if its digits are strictly increasing.
+func TestDay04CountGroupsWide(t *testing.T) {
+	binomial := func(n, k int) uint {
+		if k > n {
+			return 0
+		}
+		r := uint(1)
+		for i := 1; i <= k; i++ {
+			r = r * uint(n-k+i) / uint(i)
+		}
+		return r
+	}
+	for _, length := range []int{6, 12, 18} {
+		var want uint
+		for k := 1; k <= length; k++ {
+			want += binomial(k+8, 8) - binomial(9, k)
+		}
+		upper := 1
+		for range length {
+			upper *= 10
+		}
+		got := countGroups(0, upper-1, groupRule{2, 0})
+		if want != got {
+			t.Fatalf("up to %d digits: want %d but got %d", length, want, got)
+		}
+	}
+}
+
+func BenchmarkDay04CountGroupsWide(b *testing.B) {
 	for b.Loop() {
-		_ = Day04(false)
+		_ = countGroups(100000000000, 999999999999999999, groupRule{2, 2})
 	}
 }
@@ -232,6 +232,20 @@ func mustSplit(program string) (ic intCode) {
 	return
 }
 
+// split converts IntCode in string representation into IntCode, reporting the
+// first invalid token.
+func split(program string) (intCode, error) {
+	var ic intCode
+	for i, s := range strings.Split(program, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("opcode %d: want number but got %q", i, s)
+		}
+		ic = append(ic, n)
+	}
+	return ic, nil
+}
+
 // toString converts opcodes back to comma-separated string
 func toString(opcodes []int) string {
 	result := ""
@@ -250,7 +264,7 @@ func channels() (chan int, chan int) {
 
 // Day05 runs the diagnostic program and returns the diagnostic code
 func Day05(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
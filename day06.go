@@ -1,7 +1,12 @@
 package adventofcode2019
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -11,9 +16,12 @@ const com = "COM"
 // Day06Puzzle holds an object and its orbit.
 type Day06Puzzle struct {
 	orbits map[string]string
+	depths map[string]int // number of direct and indirect orbits
+	root   string         // the only object that orbits nothing
 }
 
-// NewDay06 creates a new Orbit from a list of 'a)b' lines.
+// NewDay06 creates a new Orbit from a list of 'a)b' lines. All objects must
+// orbit a single root, usually COM, without cycles.
 func NewDay06(ss []string) (Day06Puzzle, error) {
 	d := Day06Puzzle{
 		orbits: make(map[string]string, len(ss)),
@@ -21,15 +29,100 @@ func NewDay06(ss []string) (Day06Puzzle, error) {
 	for i, s := range ss {
 		// Inline parsing instead of strings.Split
 		idx := strings.IndexByte(s, ')')
-		if idx < 0 {
-			return d, fmt.Errorf("bad input in line %d: %s", i, s)
+		if idx <= 0 || idx == len(s)-1 {
+			return d, fmt.Errorf("line %d: want a)b but got %q", i+1, s)
 		}
 		// Save object: orbits[child] = parent
-		d.orbits[s[idx+1:]] = s[:idx]
+		child, parent := s[idx+1:], s[:idx]
+		if p, ok := d.orbits[child]; ok && p != parent {
+			return d, fmt.Errorf("line %d: %s orbits both %s and %s", i+1, child, p, parent)
+		}
+		d.orbits[child] = parent
+	}
+	if err := d.measure(); err != nil {
+		return d, err
 	}
 	return d, nil
 }
 
+// measure finds the root and the depth of every object, and rejects cycles,
+// several roots and objects that do not orbit COM.
+func (a *Day06Puzzle) measure() error {
+	a.depths = make(map[string]int, len(a.orbits)+1)
+	var roots []string
+
+	// walk up from each object until a known depth, then assign depths on
+	// the way back down
+	const walking = -1
+	var path []string
+	for object := range a.orbits {
+		path = path[:0]
+		for {
+			if d, ok := a.depths[object]; ok {
+				if d == walking {
+					return cycleError(path[slices.Index(path, object):])
+				}
+				break
+			}
+			parent, ok := a.orbits[object]
+			if !ok {
+				roots = append(roots, object)
+				a.depths[object] = 0
+				break
+			}
+			a.depths[object] = walking
+			path = append(path, object)
+			object = parent
+		}
+		depth := a.depths[object]
+		for i := len(path) - 1; i >= 0; i-- {
+			depth++
+			a.depths[path[i]] = depth
+		}
+	}
+
+	slices.Sort(roots)
+	switch {
+	case len(roots) == 1:
+		a.root = roots[0]
+	case slices.Contains(roots, com):
+		var orphans []string
+		for object := range a.depths {
+			if a.rootOf(object) != com {
+				orphans = append(orphans, object)
+			}
+		}
+		slices.Sort(orphans)
+		return fmt.Errorf("objects do not orbit %s: %s", com, strings.Join(orphans, ", "))
+	case len(roots) > 1:
+		return fmt.Errorf("want one root but got %s", strings.Join(roots, ", "))
+	}
+	return nil
+}
+
+// cycleError reports objects that orbit each other in a ring, each object
+// orbiting the next one. The ring starts at its smallest object so that the
+// error does not depend on where the walk entered it.
+func cycleError(ring []string) error {
+	i := slices.Index(ring, slices.Min(ring))
+	cycle := append(slices.Clone(ring[i:]), ring[:i]...)
+	cycle = append(cycle, cycle[0])
+	slices.Reverse(cycle)
+	return fmt.Errorf("cycle %s", strings.Join(cycle, ")"))
+}
+
+// rootOf returns the object that object orbits indirectly and that orbits
+// nothing.
+func (a Day06Puzzle) rootOf(object string) string {
+	for {
+		parent, ok := a.orbits[object]
+		if !ok {
+			return object
+		}
+		object = parent
+	}
+}
+
 // orbit returns the orbit of an object.
 func (a Day06Puzzle) orbit(object string) string {
 	return a.orbits[object]
@@ -37,55 +130,32 @@ func (a Day06Puzzle) orbit(object string) string {
 
 // orbitCount returns the number of orbits of a given object.
 func (a Day06Puzzle) orbitCount(object string) int {
-	n := 0
-	for object != com {
-		object = a.orbit(object)
-		n++
-	}
-	return n
+	return a.depths[object]
 }
 
 // orbitCountChecksum returns the checksum for a complete orbit.
 func (a Day06Puzzle) orbitCountChecksum() int {
-	// Memoize orbit counts to avoid repeated tree traversals
-	cache := make(map[string]int, len(a.orbits))
-	var orbitCount func(object string) int
-	orbitCount = func(object string) int {
-		if object == com {
-			return 0
-		}
-		if n, ok := cache[object]; ok {
-			return n
-		}
-		n := 1 + orbitCount(a.orbits[object])
-		cache[object] = n
-		return n
-	}
-
 	sum := 0
-	for object := range a.orbits {
-		sum += orbitCount(object)
+	for _, n := range a.depths {
+		sum += n
 	}
 	return sum
 }
 
-// commonOrbit returns the nearest orbit of two objects, at least COM.
+// commonOrbit returns the nearest orbit of two objects, at least the root.
 func (a Day06Puzzle) commonOrbit(object1, object2 string) string {
 	// align both objects to same orbit distance
-	for a.orbitCount(object1) > a.orbitCount(object2) {
+	for a.depths[object1] > a.depths[object2] {
 		object1 = a.orbit(object1)
 	}
-	for a.orbitCount(object2) > a.orbitCount(object1) {
+	for a.depths[object2] > a.depths[object1] {
 		object2 = a.orbit(object2)
 	}
-	for object1 != com && object2 != com {
-		if object1 == object2 {
-			return object1
-		}
+	for object1 != object2 {
 		object1 = a.orbit(object1)
 		object2 = a.orbit(object2)
 	}
-	return com
+	return object1
 }
 
 // transfers counts the number of hops between an object up to the nearest
@@ -96,10 +166,70 @@ func (a Day06Puzzle) transfers(object1, object2 string) int {
 	return (a.orbitCount(object1) - 1 - nc) + (a.orbitCount(object2) - 1 - nc)
 }
 
+// Root returns the object that all others orbit directly or indirectly.
+func (a Day06Puzzle) Root() string {
+	return a.root
+}
+
+// Path returns the shortest way from one object to another along orbits,
+// both included: up to their nearest common orbit and down again. Part 2
+// moves between the objects that YOU and SAN orbit, which takes len(path)-3
+// orbital transfers.
+func (a Day06Puzzle) Path(from, to string) ([]string, error) {
+	for _, object := range []string{from, to} {
+		if _, ok := a.depths[object]; !ok {
+			return nil, fmt.Errorf("unknown object %q", object)
+		}
+	}
+	c := a.commonOrbit(from, to)
+	var up, down []string
+	for object := from; object != c; object = a.orbit(object) {
+		up = append(up, object)
+	}
+	for object := to; object != c; object = a.orbit(object) {
+		down = append(down, object)
+	}
+	path := append(up, c)
+	slices.Reverse(down)
+	return append(path, down...), nil
+}
+
+// Histogram returns the number of objects per depth, the root is the only
+// object at depth 0.
+func (a Day06Puzzle) Histogram() []int {
+	var h []int
+	for _, d := range a.depths {
+		for len(h) <= d {
+			h = append(h, 0)
+		}
+		h[d]++
+	}
+	return h
+}
+
+// WriteDOT writes the orbits as a Graphviz digraph, an edge from each object
+// to the objects that orbit it.
+func (a Day06Puzzle) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph orbits {\n")
+	for _, child := range slices.Sorted(maps.Keys(a.orbits)) {
+		fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(a.orbits[child]), strconv.Quote(child))
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
 // Day06 solves Universal Orbit Map puzzle
-func Day06(puzzle Day06Puzzle, part1 bool) uint {
+func Day06(puzzle Day06Puzzle, part1 bool) (uint, error) {
 	if part1 {
-		return uint(puzzle.orbitCountChecksum())
+		return uint(puzzle.orbitCountChecksum()), nil
+	}
+	path, err := puzzle.Path("YOU", "SAN")
+	if err != nil {
+		return 0, err
+	}
+	if len(path) < 3 {
+		return 0, fmt.Errorf("YOU and SAN orbit each other")
 	}
-	return uint(puzzle.transfers("YOU", "SAN"))
+	return uint(len(path) - 3), nil
 }
@@ -1,6 +1,10 @@
 package adventofcode2019
 
-import "testing"
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
 
 func day06Puzzle(tb testing.TB, filename string) Day06Puzzle {
 	tb.Helper()
@@ -73,12 +77,21 @@ func TestDay6Part1(t *testing.T) {
 	}
 }
 
+// day06 parses and solves, for testLines and benchLines.
+func day06(lines []string, part1 bool) (uint, error) {
+	puzzle, err := NewDay06(lines)
+	if err != nil {
+		return 0, err
+	}
+	return Day06(puzzle, part1)
+}
+
 func TestDay06Part1(t *testing.T) {
-	testWithParser(t, 6, filename, true, NewDay06, Day06, uint(142497))
+	testLines(t, 6, filename, true, day06, uint(142497))
 }
 
 func BenchmarkDay06Part1(b *testing.B) {
-	benchWithParser(b, 6, true, NewDay06, Day06)
+	benchLines(b, 6, true, day06)
 }
 
 func TestDay6CommonOrbit(t *testing.T) {
@@ -116,9 +129,102 @@ func TestDay6Part2(t *testing.T) {
 }
 
 func TestDay06Part2(t *testing.T) {
-	testWithParser(t, 6, filename, false, NewDay06, Day06, uint(301))
+	testLines(t, 6, filename, false, day06, uint(301))
 }
 
 func BenchmarkDay06Part2(b *testing.B) {
-	benchWithParser(b, 6, false, NewDay06, Day06)
+	benchLines(b, 6, false, day06)
+}
+
+func TestDay06Path(t *testing.T) {
+	d := day06Puzzle(t, example2Filename(6))
+	tests := []struct {
+		from, to string
+		want     []string
+	}{
+		{"YOU", "SAN", []string{"YOU", "K", "J", "E", "D", "I", "SAN"}},
+		{"SAN", "COM", []string{"SAN", "I", "D", "C", "B", "COM"}},
+		{"COM", "H", []string{"COM", "B", "G", "H"}},
+		{"L", "L", []string{"L"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"-"+tt.to, func(t *testing.T) {
+			got, err := d.Path(tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(tt.want, got) {
+				t.Fatalf("want %v but got %v", tt.want, got)
+			}
+		})
+	}
+	if _, err := d.Path("YOU", "ME"); err == nil || err.Error() != `unknown object "ME"` {
+		t.Fatalf("want unknown object but got %v", err)
+	}
+}
+
+func TestDay06Histogram(t *testing.T) {
+	d := day06Puzzle(t, exampleFilename(6))
+	want := []int{1, 1, 2, 2, 2, 2, 1, 1}
+	if got := d.Histogram(); !slices.Equal(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+	if got := d.Root(); got != com {
+		t.Fatalf("want root %s but got %s", com, got)
+	}
+}
+
+func TestDay06DOT(t *testing.T) {
+	d, err := NewDay06([]string{"COM)B", "B)C", "B)A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := d.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "digraph orbits {\n\t\"B\" -> \"A\";\n\t\"COM\" -> \"B\";\n\t\"B\" -> \"C\";\n}\n"
+	if got := buf.String(); want != got {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
+
+func TestDay06OtherRoot(t *testing.T) {
+	d, err := NewDay06([]string{"SUN)EARTH", "EARTH)MOON", "SUN)YOU", "MOON)SAN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Root(); got != "SUN" {
+		t.Fatalf("want root SUN but got %s", got)
+	}
+	for part1, want := range map[bool]uint{true: 7, false: 2} {
+		got, err := Day06(d, part1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want != got {
+			t.Fatalf("part1=%t: want %d but got %d", part1, want, got)
+		}
+	}
+}
+
+func TestDay06Errors(t *testing.T) {
+	parse := func(lines []string, _ bool) (Day06Puzzle, error) {
+		return NewDay06(lines)
+	}
+	testErrors(t, parse, []errorCase[[]string]{
+		{"syntax", []string{"COM)B", "B-C"}, true, `line 2: want a)b but got "B-C"`},
+		{"no child", []string{"COM)"}, true, `line 1: want a)b but got "COM)"`},
+		{"two orbits", []string{"COM)B", "COM)C", "C)B"}, true, "line 3: B orbits both COM and C"},
+		{"cycle", []string{"COM)B", "X)Y", "Y)Z", "Z)X"}, true, "cycle X)Y)Z)X"},
+		{"orphans", []string{"COM)B", "X)Y", "Y)Z"}, true, "objects do not orbit COM: X, Y, Z"},
+		{"roots", []string{"A)B", "X)Y"}, true, "want one root but got A, X"},
+	})
+	d, err := NewDay06([]string{"COM)YOU", "YOU)SAN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Day06(d, false); err == nil {
+		t.Fatal("want error for SAN orbiting YOU")
+	}
 }
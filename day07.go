@@ -1,26 +1,44 @@
 package adventofcode2019
 
+import "fmt"
+
 // Day07 computes maximum thruster signal for amplifier circuits
 func Day07(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
+	var thrust int
 	if part1 {
-		return uint(day7Part1(ic)), nil
+		thrust, err = day7Part1(ic)
+	} else {
+		thrust, err = day7Part2(ic)
 	}
-	return uint(day7Part2(ic)), nil
+	return uint(thrust), err
+}
+
+// ampError names the amplifier, A to E, and the phase settings of a fault.
+func ampError(perm []int, amp int, err error) error {
+	return fmt.Errorf("phases %v, amplifier %c: %w", perm, 'A'+amp, err)
 }
 
-func day7Part1(ic *intcode) int {
+func day7Part1(ic *Intcode) (int, error) {
 	maxThrust := 0
 	phases := []int{0, 1, 2, 3, 4}
 
+	var err error
 	permute(phases, func(perm []int) {
+		if err != nil {
+			return
+		}
 		signal := 0
-		for _, phase := range perm {
+		for i, phase := range perm {
 			ic.Reset()
-			outputs, _ := ic.Run(phase, signal)
+			outputs, rerr := ic.Run(phase, signal)
+			if rerr != nil {
+				err = ampError(perm, i, rerr)
+				return
+			}
 			if len(outputs) > 0 {
 				signal = outputs[0]
 			}
@@ -30,72 +48,50 @@ func day7Part1(ic *intcode) int {
 		}
 	})
 
-	return maxThrust
+	return maxThrust, err
 }
 
-func day7Part2(ic *intcode) int {
+func day7Part2(ic *Intcode) (int, error) {
 	maxThrust := 0
 	phases := []int{5, 6, 7, 8, 9}
 
+	var err error
 	permute(phases, func(perm []int) {
-		// Create 5 amplifiers
-		amps := make([]*intcode, 5)
-		for i := range 5 {
-			amps[i] = ic.Clone()
+		if err != nil {
+			return
 		}
-
-		// Initialize each amp with its phase setting
+		// Create 5 amplifiers, each initialized with its phase setting
+		amps := make([]*Intcode, 5)
 		for i, phase := range perm {
-			runUntilNeedsInput(amps[i])
-			amps[i].Input(phase)
+			amps[i] = ic.Clone()
+			if _, _, rerr := amps[i].RunUntilInput(phase); rerr != nil {
+				err = ampError(perm, i, rerr)
+				return
+			}
 		}
 
-		// Run feedback loop
+		// Run feedback loop until the last amplifier halts
 		signal := 0
-		lastOutput := 0
-		done := 0
-
-		for done < 5 {
-			for i := range 5 {
-				if amps[i] == nil {
-					continue
+		for running := true; running; {
+			for i, amp := range amps {
+				outputs, state, rerr := amp.RunUntilInput(signal)
+				if rerr != nil {
+					err = ampError(perm, i, rerr)
+					return
 				}
-
-				// Run until needs input or has output or halted
-				for {
-					state := amps[i].Step()
-					switch state {
-					case needsInput:
-						amps[i].Input(signal)
-					case hasOutput:
-						signal = amps[i].Output()
-						lastOutput = signal
-						goto nextAmp
-					case halted:
-						done++
-						amps[i] = nil
-						goto nextAmp
-					}
+				if len(outputs) > 0 {
+					signal = outputs[len(outputs)-1]
 				}
-			nextAmp:
+				running = state == NeedsInput
 			}
 		}
 
-		if lastOutput > maxThrust {
-			maxThrust = lastOutput
+		if signal > maxThrust {
+			maxThrust = signal
 		}
 	})
 
-	return maxThrust
-}
-
-func runUntilNeedsInput(ic *intcode) {
-	for {
-		state := ic.Step()
-		if state == needsInput || state == halted {
-			return
-		}
-	}
+	return maxThrust, err
 }
 
 // permute calls f with each permutation of a
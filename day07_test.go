@@ -23,3 +23,10 @@ func BenchmarkDay07Part2(b *testing.B) {
 		_, _ = Day07(buf, false)
 	}
 }
+
+func TestDay07Errors(t *testing.T) {
+	testErrors(t, fromString(Day07), []errorCase[string]{
+		{"fault part 1", "42,0,0,0", true, "phases [0 1 2 3 4], amplifier A: illegal opcode 42 at address 0"},
+		{"fault part 2", "42,0,0,0", false, "phases [5 6 7 8 9], amplifier A: illegal opcode 42 at address 0"},
+	})
+}
@@ -1,82 +1,191 @@
 package adventofcode2019
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
 
-// day8Part1 returns number of 1s multiplied by number of 2s in the layer having
-// minimal number of 0s.
-func day8Part1(digits []byte) (int, error) {
-	width := 25
-	height := 6
-	size := width * height
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/ocr"
+)
 
-	if len(digits)%size != 0 {
-		return -1, fmt.Errorf("cannot pack %d digits into %dx%d layer",
+// Size of the images sent by the Elves.
+const (
+	day08Width  = 25
+	day08Height = 6
+)
+
+// Pixel digits of the Space Image Format.
+const (
+	pixelBlack       = '0'
+	pixelWhite       = '1'
+	pixelTransparent = '2'
+)
+
+// SpaceImage is an image in Space Image Format, layers of width by height
+// digits, front layer first.
+type SpaceImage struct {
+	Width, Height int
+	Layers        [][]byte // digits, row major
+}
+
+// LayerStats counts the digits of one layer.
+type LayerStats struct {
+	Layer  int
+	Counts [10]int // Counts[0] is the number of '0' digits
+}
+
+// NewSpaceImage splits digits into layers of width by height. Trailing
+// whitespace such as a final newline is ignored.
+func NewSpaceImage(digits []byte, width, height int) (*SpaceImage, error) {
+	if width < 1 || height < 1 {
+		return nil, fmt.Errorf("want positive dimensions but got %dx%d", width, height)
+	}
+	digits = bytes.TrimRight(digits, " \t\r\n")
+	size := width * height
+	if len(digits) == 0 || len(digits)%size != 0 {
+		return nil, fmt.Errorf("cannot pack %d digits into %dx%d layer",
 			len(digits), width, height)
 	}
-	layers := len(digits) / size
-	// count zeroes in layer N
-	zeroes := make([]int, layers)
-	for i := 0; i < len(digits); i++ {
-		if digits[i] == '0' {
-			zeroes[i/size]++
+	for i, b := range digits {
+		if b < '0' || b > '9' {
+			return nil, fmt.Errorf("want digit but got %q at position %d", b, i+1)
 		}
 	}
-	// minimal layer
-	min, minLayer := size, layers
-	for i := range zeroes {
-		if zeroes[i] < min {
-			min = zeroes[i]
-			minLayer = i
-		}
+	img := &SpaceImage{Width: width, Height: height, Layers: make([][]byte, 0, len(digits)/size)}
+	for i := 0; i < len(digits); i += size {
+		img.Layers = append(img.Layers, digits[i:i+size])
 	}
-	ones := 0
-	twos := 0
-	for i := minLayer * size; i < (minLayer+1)*size; i++ {
-		if digits[i] == '1' {
-			ones++
-		} else if digits[i] == '2' {
-			twos++
+	return img, nil
+}
+
+// Stats returns the digit counts of each layer.
+func (a *SpaceImage) Stats() []LayerStats {
+	stats := make([]LayerStats, len(a.Layers))
+	for i, layer := range a.Layers {
+		stats[i].Layer = i
+		for _, b := range layer {
+			stats[i].Counts[b-'0']++
 		}
 	}
-	return ones * twos, nil
+	return stats
 }
 
-// day8Part2 returns rendered layer.
-func day8Part2(digits []byte) ([]byte, error) {
-	width := 25
-	height := 6
-	size := width * height
-	rendered := make([]byte, size)
-
-	if len(digits)%size != 0 {
-		return rendered, fmt.Errorf("cannot pack %d digits into %dx%d layer",
-			len(digits), width, height)
+// Checksum returns number of 1s multiplied by number of 2s in the layer
+// having minimal number of 0s.
+func (a *SpaceImage) Checksum() int {
+	stats := a.Stats()
+	best := stats[0]
+	for _, s := range stats[1:] {
+		if s.Counts[0] < best.Counts[0] {
+			best = s
+		}
 	}
-	renderedPixel := func(i int) byte {
+	return best.Counts[1] * best.Counts[2]
+}
+
+// Render stacks the layers, each pixel takes the digit of the first layer
+// where it is not transparent. Pixels that are transparent in all layers
+// stay transparent.
+func (a *SpaceImage) Render() []byte {
+	rendered := make([]byte, a.Width*a.Height)
+	for i := range rendered {
 		// drill through layers as long as pixel is transparent
-		// assume one pixel MUST be colored, otherwise we will run out
-		// of layers and panic badly
-		for digits[i] == '2' {
-			i += size
+		rendered[i] = pixelTransparent
+		for _, layer := range a.Layers {
+			if layer[i] != pixelTransparent {
+				rendered[i] = layer[i]
+				break
+			}
+		}
+	}
+	return rendered
+}
+
+// Transparent returns the number of pixels that are transparent in all
+// layers.
+func (a *SpaceImage) Transparent() int {
+	return bytes.Count(a.Render(), []byte{pixelTransparent})
+}
+
+// Frame returns the rendered image, black as ' ', white as '#' and
+// transparent as '?'. Transparent pixels have no color in PNG and are black
+// in PBM.
+func (a *SpaceImage) Frame() *Frame {
+	palette := []Ink{{' ', black}, {'#', white}, {'?', color.RGBA{}}}
+	f := NewFrame(a.Width, a.Height, palette)
+	for i, b := range a.Render() {
+		switch b {
+		case pixelWhite:
+			f.Cells[i] = 1
+		case pixelTransparent:
+			f.Cells[i] = 2
 		}
-		return digits[i]
 	}
-	for pixel := range rendered {
-		rendered[pixel] = renderedPixel(pixel)
+	return f
+}
+
+// WriteASCII writes a preview of the rendered image, see Frame.
+func (a *SpaceImage) WriteASCII(w io.Writer) error {
+	return a.Frame().WriteASCII(w)
+}
+
+// WritePBM writes the rendered image as a plain portable bitmap.
+func (a *SpaceImage) WritePBM(w io.Writer) error {
+	return a.Frame().WritePBM(w)
+}
+
+// WritePNG writes the rendered image as PNG, each pixel a square of scale.
+func (a *SpaceImage) WritePNG(w io.Writer, scale int) error {
+	return a.Frame().WritePNG(w, scale)
+}
+
+// Text reads the letters of the rendered image.
+func (a *SpaceImage) Text() (string, error) {
+	rendered := a.Render()
+	return ocr.ReadText(a.Width, a.Height, func(x, y int) bool {
+		return rendered[y*a.Width+x] == pixelWhite
+	})
+}
+
+// day8Part1 returns number of 1s multiplied by number of 2s in the layer having
+// minimal number of 0s.
+func day8Part1(digits []byte) (int, error) {
+	img, err := NewSpaceImage(digits, day08Width, day08Height)
+	if err != nil {
+		return -1, err
+	}
+	return img.Checksum(), nil
+}
+
+// day8Part2 returns rendered layer.
+func day8Part2(digits []byte) ([]byte, error) {
+	img, err := NewSpaceImage(digits, day08Width, day08Height)
+	if err != nil {
+		return nil, err
+	}
+	return img.Render(), nil
+}
+
+// day08Frames draws the rendered image.
+func day08Frames(digits []byte) ([]*Frame, error) {
+	img, err := NewSpaceImage(digits, day08Width, day08Height)
+	if err != nil {
+		return nil, err
 	}
-	return rendered, nil
+	return []*Frame{img.Frame()}, nil
 }
 
-// Day08 solves Space Image Format puzzle
-func Day08(input []byte, part1 bool) uint {
+// Day08 solves Space Image Format puzzle. Part 1 returns the checksum, part
+// 2 the letters of the rendered image.
+func Day08(input []byte, part1 bool) (Answer, error) {
+	img, err := NewSpaceImage(input, day08Width, day08Height)
+	if err != nil {
+		return Answer{}, err
+	}
 	if part1 {
-		checksum, err := day8Part1(input)
-		if err != nil {
-			return 0
-		}
-		return uint(checksum)
+		return Answer{Number: uint(img.Checksum())}, nil
 	}
-	// Part2 renders ASCII art, no numeric return value
-	_, _ = day8Part2(input)
-	return 0
+	text, err := img.Text()
+	return Answer{Text: text}, err
 }
@@ -3,6 +3,7 @@ package adventofcode2019
 import (
 	"bytes"
 	"fmt"
+	"image/png"
 	"os"
 	"reflect"
 	"testing"
@@ -24,18 +25,13 @@ func TestDay8Part1(t *testing.T) {
 }
 
 func TestDay08Part1(t *testing.T) {
-	buf := fileFromFilename(t, filename, 8)
-	want := uint(1463)
-	got := Day08(buf, true)
-	if want != got {
-		t.Fatalf("want %d but got %d", want, got)
-	}
+	testSolver(t, 8, filename, true, Day08, Answer{Number: 1463})
 }
 
 func BenchmarkDay08Part1(b *testing.B) {
 	buf := fileFromFilename(b, filename, 8)
 	for b.Loop() {
-		_ = Day08(buf, true)
+		_, _ = Day08(buf, true)
 	}
 }
 
@@ -81,17 +77,115 @@ func TestDay8Part2(t *testing.T) {
 }
 
 func TestDay08Part2(t *testing.T) {
-	buf := fileFromFilename(t, filename, 8)
-	// Part2 renders ASCII art, Day08 returns 0
-	got := Day08(buf, false)
-	if got != 0 {
-		t.Fatalf("want 0 but got %d", got)
-	}
+	// letters as in testdata/day08-part2-result.txt
+	testSolver(t, 8, filename, false, Day08, Answer{Text: "GKCKH"})
 }
 
 func BenchmarkDay08Part2(b *testing.B) {
 	buf := fileFromFilename(b, filename, 8)
 	for b.Loop() {
-		_ = Day08(buf, false)
+		_, _ = Day08(buf, false)
+	}
+}
+
+func TestSpaceImageStats(t *testing.T) {
+	img, err := NewSpaceImage([]byte("123456789012\n"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LayerStats{
+		{0, [10]int{0, 1, 1, 1, 1, 1, 1}},
+		{1, [10]int{1, 1, 1, 0, 0, 0, 0, 1, 1, 1}},
+	}
+	if got := img.Stats(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+	if got := img.Checksum(); got != 1 {
+		t.Fatalf("want checksum 1 but got %d", got)
+	}
+}
+
+func TestSpaceImageRender(t *testing.T) {
+	img, err := NewSpaceImage([]byte("0222112222120000"), 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(img.Render()); got != "0110" {
+		t.Fatalf("want 0110 but got %s", got)
+	}
+	var buf bytes.Buffer
+	if err := img.WriteASCII(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != " #\n# \n" {
+		t.Fatalf("want preview %q but got %q", " #\n# \n", got)
+	}
+	buf.Reset()
+	if err := img.WritePBM(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "P1\n2 2\n1 0\n0 1\n", buf.String(); want != got {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
+
+// TestSpaceImageTransparent renders a pixel that is transparent in every
+// layer, which used to run past the last layer.
+func TestSpaceImageTransparent(t *testing.T) {
+	img, err := NewSpaceImage([]byte("2120"+"2202"), 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(img.Render()); got != "2100" {
+		t.Fatalf("want 2100 but got %s", got)
+	}
+	if got := img.Transparent(); got != 1 {
+		t.Fatalf("want 1 transparent pixel but got %d", got)
+	}
+	var buf bytes.Buffer
+	if err := img.WriteASCII(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "?#\n  \n" {
+		t.Fatalf("want preview %q but got %q", "?#\n  \n", got)
+	}
+	buf.Reset()
+	if err := img.WritePNG(&buf, 3); err != nil {
+		t.Fatal(err)
+	}
+	m, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := m.At(1, 1).RGBA(); a != 0 {
+		t.Fatalf("want transparent pixel but got alpha %d", a)
+	}
+	if _, _, _, a := m.At(4, 1).RGBA(); a == 0 {
+		t.Fatal("want opaque pixel but got transparent")
+	}
+	if b := m.Bounds(); b.Dx() != 6 || b.Dy() != 6 {
+		t.Fatalf("want 6x6 pixels but got %v", b)
+	}
+}
+
+func TestSpaceImageErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		digits        string
+		width, height int
+		want          string
+	}{
+		{"dimensions", "0000", 0, 2, "want positive dimensions but got 0x2"},
+		{"partial layer", "00000", 2, 2, "cannot pack 5 digits into 2x2 layer"},
+		{"empty", "\n", 2, 2, "cannot pack 0 digits into 2x2 layer"},
+		{"digit", "00x0", 2, 2, `want digit but got 'x' at position 3`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSpaceImage([]byte(tt.digits), tt.width, tt.height)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("want error %q but got %v", tt.want, err)
+			}
+		})
 	}
 }
@@ -1,20 +1,139 @@
 package adventofcode2019
 
-// Day09 runs the BOOST program and returns the output
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// boostTestMode is the input that makes BOOST check the VM.
+const boostTestMode = 1
+
+// opcodeNames are the names the puzzles use for the built-in opcodes.
+var opcodeNames = map[int]string{
+	1:  "add",
+	2:  "multiply",
+	3:  "input",
+	4:  "output",
+	5:  "jump-if-true",
+	6:  "jump-if-false",
+	7:  "less than",
+	8:  "equals",
+	9:  "adjust relative base",
+	99: "halt",
+}
+
+// modeNames are the parameter modes by number.
+var modeNames = []string{"position", "immediate", "relative"}
+
+// BoostFault is an instruction that BOOST found to malfunction, opcode and
+// parameter modes as one number, e.g. 203 for input in relative mode.
+type BoostFault int
+
+// Opcode returns the opcode without modes.
+func (a BoostFault) Opcode() int {
+	return int(a) % 100
+}
+
+// Modes returns the mode of each parameter of the opcode.
+func (a BoostFault) Modes() []int {
+	op, ok := builtins[a.Opcode()]
+	if !ok {
+		return nil
+	}
+	modes := make([]int, op.Arity)
+	for n := range modes {
+		modes[n] = (int(a) / pow10(n+2)) % 10
+	}
+	return modes
+}
+
+// String describes the defect, e.g. "203: input (relative)".
+func (a BoostFault) String() string {
+	name, ok := opcodeNames[a.Opcode()]
+	if !ok {
+		return fmt.Sprintf("%d: unknown opcode %d", int(a), a.Opcode())
+	}
+	modes := a.Modes()
+	if len(modes) == 0 {
+		return fmt.Sprintf("%d: %s", int(a), name)
+	}
+	names := make([]string, len(modes))
+	for i, m := range modes {
+		if m < len(modeNames) {
+			names[i] = modeNames[m]
+		} else {
+			names[i] = fmt.Sprintf("mode %d", m)
+		}
+	}
+	return fmt.Sprintf("%d: %s (%s)", int(a), name, strings.Join(names, ", "))
+}
+
+// BoostReport is the outcome of BOOST in test mode: the instructions that
+// malfunctioned, then the keycode. A VM that works reports no faults.
+type BoostReport struct {
+	Faults  []BoostFault
+	Keycode int
+}
+
+// newBoostReport decodes the outputs of BOOST in test mode, faulty
+// instructions first and the keycode last.
+func newBoostReport(outputs []int) (BoostReport, error) {
+	if len(outputs) == 0 {
+		return BoostReport{}, errors.New("BOOST reported nothing")
+	}
+	var r BoostReport
+	for _, o := range outputs[:len(outputs)-1] {
+		r.Faults = append(r.Faults, BoostFault(o))
+	}
+	r.Keycode = outputs[len(outputs)-1]
+	return r, nil
+}
+
+// Err returns an error listing the faults, nil if there are none.
+func (a BoostReport) Err() error {
+	if len(a.Faults) == 0 {
+		return nil
+	}
+	defects := make([]string, len(a.Faults))
+	for i, f := range a.Faults {
+		defects[i] = f.String()
+	}
+	return fmt.Errorf("VM defects: %s", strings.Join(defects, "; "))
+}
+
+// BoostDiagnostic runs the BOOST program in test mode on a clone of ic and
+// reports which instructions malfunction.
+func BoostDiagnostic(ic *Intcode) (BoostReport, error) {
+	ic = ic.Clone()
+	outputs, err := ic.Run(boostTestMode)
+	if err != nil {
+		return BoostReport{}, err
+	}
+	return newBoostReport(outputs)
+}
+
+// Day09 runs the BOOST program. Part 1 returns the keycode of test mode and
+// fails if BOOST reports defects, part 2 the coordinates of the distress
+// signal.
 func Day09(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
 
-	var inputVal int
 	if part1 {
-		inputVal = 1
-	} else {
-		inputVal = 2
+		r, err := BoostDiagnostic(ic)
+		if err != nil {
+			return 0, err
+		}
+		if err := r.Err(); err != nil {
+			return 0, err
+		}
+		return uint(r.Keycode), nil
 	}
 
-	outputs, err := ic.Run(inputVal)
+	outputs, err := ic.Run(2)
 	if err != nil {
 		return 0, err
 	}
@@ -1,6 +1,9 @@
 package adventofcode2019
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestDay09Part1(t *testing.T) {
 	testSolver(t, 9, filename, true, Day09, uint(2436480432))
@@ -10,6 +13,43 @@ func TestDay09Part2(t *testing.T) {
 	testSolver(t, 9, filename, false, Day09, uint(45710))
 }
 
+func TestBoostReport(t *testing.T) {
+	tests := []struct {
+		outputs []int
+		modes   [][]int
+		want    string
+	}{
+		// a VM that writes relative mode input to position mode reports 203
+		{[]int{203, 0}, [][]int{{2}}, "VM defects: 203: input (relative)"},
+		{[]int{1102, 0}, [][]int{{1, 1, 0}}, "VM defects: 1102: multiply (immediate, immediate, position)"},
+		{[]int{21108, 99, 0}, [][]int{{1, 1, 2}, nil},
+			"VM defects: 21108: equals (immediate, immediate, relative); 99: halt"},
+		{[]int{42, 304, 0}, [][]int{nil, {3}}, "VM defects: 42: unknown opcode 42; 304: output (mode 3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			r, err := newBoostReport(tt.outputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, f := range r.Faults {
+				if !slices.Equal(tt.modes[i], f.Modes()) {
+					t.Fatalf("%d: want modes %v but got %v", f, tt.modes[i], f.Modes())
+				}
+			}
+			if err := r.Err(); err == nil || err.Error() != tt.want {
+				t.Fatalf("want %q but got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestBoostReportEmpty(t *testing.T) {
+	if _, err := newBoostReport(nil); err == nil {
+		t.Fatal("want error")
+	}
+}
+
 func BenchmarkDay09Part1(b *testing.B) {
 	buf := fileFromFilename(b, filename, 9)
 	for b.Loop() {
@@ -1,9 +1,14 @@
 package adventofcode2019
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
 	"image"
+	"image/color"
 	"math"
-	"sort"
+	"slices"
 )
 
 // direction represents a normalized direction vector for line-of-sight.
@@ -34,146 +39,206 @@ func normalize(dx, dy int) direction {
 	return direction{dx / g, dy / g}
 }
 
-// parseAsteroidMap parses newline separated strings into asteroids.
-func parseAsteroidMap(asteroids []byte) []image.Point {
-	isAsteroid := func(b byte) bool {
-		return b == '#'
-	}
-	isEmpty := func(b byte) bool {
-		return b == '.'
-	}
-	isNewline := func(b byte) bool {
-		return b == '\n'
-	}
-	isWhitespace := func(b byte) bool {
-		return !(isAsteroid(b) || isEmpty(b))
+// angle returns the direction in degrees clockwise from up, 0 <= angle < 360.
+func (a direction) angle() float64 {
+	// Y axis points down, so up is negative Y
+	deg := math.Atan2(float64(a.dx), float64(-a.dy)) * 180 / math.Pi
+	if deg < 0 {
+		deg += 360
 	}
+	return deg
+}
 
-	// overread any leading whitespace
-	start := 0
-	for start < len(asteroids) && isWhitespace(asteroids[start]) {
-		start++
-	}
+// AsteroidField is a map of asteroids, X to the right and Y down from the
+// top left corner.
+type AsteroidField struct {
+	Width, Height int
+	Asteroids     []image.Point // row by row
+	Station       image.Point   // position marked 'X', if HasStation
+	HasStation    bool
+}
 
-	// Count asteroids first to preallocate
-	count := 0
-	for _, b := range asteroids[start:] {
-		if isAsteroid(b) {
-			count++
+// NewAsteroidField parses rows of '#' for an asteroid and '.' for empty
+// space. An 'X' marks the station, which sits on an asteroid.
+func NewAsteroidField(input []byte) (*AsteroidField, error) {
+	var a AsteroidField
+	for i, row := range bytes.Split(bytes.TrimSpace(input), []byte("\n")) {
+		row = bytes.TrimSuffix(row, []byte("\r"))
+		for x, b := range row {
+			p := image.Point{X: x, Y: i}
+			switch b {
+			case '#':
+				a.Asteroids = append(a.Asteroids, p)
+			case 'X':
+				if a.HasStation {
+					return nil, fmt.Errorf("line %d: second station", i+1)
+				}
+				a.Station, a.HasStation = p, true
+				a.Asteroids = append(a.Asteroids, p)
+			case '.':
+			default:
+				return nil, fmt.Errorf("line %d: unexpected %q", i+1, b)
+			}
 		}
+		a.Width, a.Height = max(a.Width, len(row)), i+1
 	}
-
-	as := make([]image.Point, 0, count)
-	y := 0
-	x := 0
-	for _, b := range asteroids[start:] {
-		if isEmpty(b) {
-			x++
-		} else if isNewline(b) {
-			x = 0
-			y++
-		} else if isAsteroid(b) {
-			as = append(as, image.Point{X: x, Y: y})
-			x++
-		}
+	if len(a.Asteroids) == 0 {
+		return nil, errors.New("no asteroids")
 	}
-	return as
+	return &a, nil
 }
 
-// day10Part1 returns the asteroid that can see most asteroids, and the number of
-// visible asteroids.
-func day10Part1(as []image.Point) (image.Point, int) {
-	var best image.Point
-	maxVisible := 0
-	for i := range as {
-		// map of normalized directions - each unique direction = one visible asteroid
-		visible := make(map[direction]bool, len(as))
-		for j := range as {
+// Visibility returns the number of asteroids that each asteroid can see,
+// in the order of Asteroids.
+func (a *AsteroidField) Visibility() []int {
+	counts := make([]int, len(a.Asteroids))
+	// map of normalized directions - each unique direction = one visible asteroid
+	visible := make(map[direction]bool, len(a.Asteroids))
+	for i, from := range a.Asteroids {
+		clear(visible)
+		for j, to := range a.Asteroids {
 			// skip ourself
 			if i == j {
 				continue
 			}
-			dx := as[j].X - as[i].X
-			dy := as[j].Y - as[i].Y
-			dir := normalize(dx, dy)
-			visible[dir] = true
+			visible[normalize(to.X-from.X, to.Y-from.Y)] = true
 		}
-		// found a better location?
-		if len(visible) > maxVisible {
-			best = as[i]
-			maxVisible = len(visible)
+		counts[i] = len(visible)
+	}
+	return counts
+}
+
+// Best returns the asteroid that can see most asteroids, and the number of
+// visible asteroids. Ties go to the first asteroid in reading order.
+func (a *AsteroidField) Best() (image.Point, int) {
+	var best image.Point
+	maxVisible := 0
+	for i, n := range a.Visibility() {
+		if n > maxVisible {
+			best, maxVisible = a.Asteroids[i], n
 		}
 	}
 	return best, maxVisible
 }
 
-// asteroidWithAngle stores an asteroid with its angle and distance from base.
-type asteroidWithAngle struct {
-	asteroid image.Point
-	angle    float64
-	dist     int
+// Base returns the station marked 'X' and the number of asteroids it sees,
+// or the best location if there is no mark.
+func (a *AsteroidField) Base() (image.Point, int) {
+	if !a.HasStation {
+		return a.Best()
+	}
+	i := slices.Index(a.Asteroids, a.Station)
+	return a.Station, a.Visibility()[i]
 }
 
-// day10Part2 determines the 200th asteroid that gets vaporized.
-func day10Part2(as []image.Point, base image.Point) int {
-	// Build list of asteroids with their angles and distances
-	var targets []asteroidWithAngle
-	for _, a := range as {
-		if a == base {
-			continue
-		}
-		dx := a.X - base.X
-		dy := a.Y - base.Y
-		// Calculate angle: atan2 with Y axis pointing down
-		// Rotate by 90° so "up" (negative Y) is 0
-		angle := math.Atan2(float64(dx), float64(-dy))
-		if angle < 0 {
-			angle += 2 * math.Pi
+// Heatmap draws the number of visible asteroids of each asteroid, from '0'
+// in blue for the fewest to '9' in red for the most.
+func (a *AsteroidField) Heatmap() *Frame {
+	palette := []Ink{{'.', black}}
+	for l := range 10 {
+		palette = append(palette, Ink{rune('0' + l), color.RGBA{
+			R: uint8(0xff * l / 9), G: 0x20, B: uint8(0xff * (9 - l) / 9), A: 0xff,
+		}})
+	}
+	counts := a.Visibility()
+	lo, hi := slices.Min(counts), slices.Max(counts)
+	f := NewFrame(a.Width, a.Height, palette)
+	for i, p := range a.Asteroids {
+		level := 9
+		if hi > lo {
+			level = 9 * (counts[i] - lo) / (hi - lo)
 		}
-		dist := dx*dx + dy*dy // squared distance is fine for comparison
-		targets = append(targets, asteroidWithAngle{a, angle, dist})
+		f.Set(p.X, p.Y, uint8(1+level))
 	}
+	return f
+}
 
-	// Sort by angle, then by distance
-	sort.Slice(targets, func(i, j int) bool {
-		if targets[i].angle != targets[j].angle {
-			return targets[i].angle < targets[j].angle
-		}
-		return targets[i].dist < targets[j].dist
-	})
+// Vaporization is one asteroid hit by the laser.
+type Vaporization struct {
+	Asteroid image.Point
+	Rotation int     // 1 for the first turn of the laser
+	Angle    float64 // degrees clockwise from up
+}
 
-	// Vaporize in order, cycling through angles
-	var vaporized []image.Point
-	for len(targets) > 0 {
-		lastAngle := -1.0
-		i := 0
-		for i < len(targets) {
-			if targets[i].angle != lastAngle {
-				// Vaporize this one
-				vaporized = append(vaporized, targets[i].asteroid)
-				lastAngle = targets[i].angle
-				// Remove from targets
-				targets = append(targets[:i], targets[i+1:]...)
-			} else {
-				// Same angle as last vaporized, skip for this rotation
-				i++
-			}
+// Vaporize returns the order in which a laser at station, turning clockwise
+// from start degrees clockwise from up, vaporizes all other asteroids. Each
+// turn, the laser hits the nearest remaining asteroid in each direction. The
+// station does not have to be an asteroid, an asteroid at the station is not
+// vaporized.
+func (a *AsteroidField) Vaporize(station image.Point, start float64) []Vaporization {
+	type target struct {
+		Vaporization
+		dir  direction
+		turn float64 // angle from start
+		dist int     // squared distance is fine for comparison
+	}
+	start = math.Mod(start, 360)
+	targets := make([]target, 0, len(a.Asteroids))
+	for _, p := range a.Asteroids {
+		if p == station {
+			continue
 		}
+		dx, dy := p.X-station.X, p.Y-station.Y
+		// angle of the normalized direction, so that asteroids behind
+		// each other have exactly the same angle
+		t := target{dir: normalize(dx, dy), dist: dx*dx + dy*dy}
+		t.Asteroid, t.Angle = p, t.dir.angle()
+		t.turn = math.Mod(t.Angle-start+360, 360)
+		targets = append(targets, t)
+	}
+
+	// Sort by angle, then by distance, so the nth asteroid of a direction
+	// is hit in the nth turn
+	slices.SortFunc(targets, func(x, y target) int {
+		return cmp.Or(cmp.Compare(x.turn, y.turn), cmp.Compare(x.dist, y.dist))
+	})
+	hits := make(map[direction]int, len(targets))
+	for i := range targets {
+		hits[targets[i].dir]++
+		targets[i].Rotation = hits[targets[i].dir]
 	}
+	slices.SortStableFunc(targets, func(x, y target) int {
+		return cmp.Compare(x.Rotation, y.Rotation)
+	})
 
-	// Return the 200th vaporized asteroid
-	a := vaporized[199]
-	return a.X*100 + a.Y
+	order := make([]Vaporization, len(targets))
+	for i, t := range targets {
+		order[i] = t.Vaporization
+	}
+	return order
 }
 
 // Day10 solves Monitoring Station puzzle
-func Day10(input []byte, part1 bool) uint {
-	asteroids := parseAsteroidMap(input)
-	base, maxVisible := day10Part1(asteroids)
+func Day10(input []byte, part1 bool) (uint, error) {
+	field, err := NewAsteroidField(input)
+	if err != nil {
+		return 0, err
+	}
+	base, visible := field.Base()
 
 	if part1 {
-		return uint(maxVisible)
+		return uint(visible), nil
+	}
+
+	order := field.Vaporize(base, 0)
+	if len(order) < 200 {
+		return 0, fmt.Errorf("want at least 201 asteroids but got %d", len(field.Asteroids))
 	}
+	a := order[199].Asteroid
+	return uint(a.X*100 + a.Y), nil
+}
 
-	return uint(day10Part2(asteroids, base))
+// day10Frames draws the asteroid field with the monitoring station.
+func day10Frames(input []byte) ([]*Frame, error) {
+	field, err := NewAsteroidField(input)
+	if err != nil {
+		return nil, err
+	}
+	base, _ := field.Base()
+	f := NewFrame(field.Width, field.Height, []Ink{{'.', black}, {'#', gray}, {'X', red}})
+	for _, a := range field.Asteroids {
+		f.Set(a.X, a.Y, 1)
+	}
+	f.Set(base.X, base.Y, 2)
+	return []*Frame{f}, nil
 }
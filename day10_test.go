@@ -3,6 +3,9 @@ package adventofcode2019
 import (
 	"fmt"
 	"image"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -20,7 +23,11 @@ var day10Examples = []struct {
 
 func TestDay10Example1(t *testing.T) {
 	buf := fileFromFilename(t, example1Filename, 10)
-	as := parseAsteroidMap(buf)
+	field, err := NewAsteroidField(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := field.Asteroids
 
 	// Check number of asteroids
 	if len(as) != 10 {
@@ -39,9 +46,12 @@ func TestDay10Part1Examples(t *testing.T) {
 		id := fmt.Sprintf("Day10Part1 example #%d", i+1)
 		t.Run(id, func(t *testing.T) {
 			buf := fileFromFilename(t, tt.filenameFunc, 10)
-			as := parseAsteroidMap(buf)
+			field, err := NewAsteroidField(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
 			wantA, want := tt.best, tt.bestCount
-			gotA, got := day10Part1(as)
+			gotA, got := field.Best()
 			if tt.best != gotA {
 				t.Fatalf("%s: want %+v but got %+v",
 					id, wantA, gotA)
@@ -54,44 +64,153 @@ func TestDay10Part1Examples(t *testing.T) {
 }
 
 func TestDay10Part1(t *testing.T) {
-	buf := fileFromFilename(t, filename, 10)
-	want := uint(267)
-	got := Day10(buf, true)
-	if want != got {
-		t.Fatalf("want %d but got %d", want, got)
-	}
+	testSolver(t, 10, filename, true, Day10, 267)
 }
 
 func BenchmarkDay10Part1(b *testing.B) {
-	buf := fileFromFilename(b, filename, 10)
-	for b.Loop() {
-		_ = Day10(buf, true)
-	}
+	benchSolver(b, 10, true, Day10)
 }
 
 func TestDay10Part2Example(t *testing.T) {
-	want := 802
 	ex := day10Examples[4]
 	buf := fileFromFilename(t, ex.filenameFunc, 10)
-	as := parseAsteroidMap(buf)
-	got := day10Part2(as, ex.best)
-	if want != got {
-		t.Fatalf("want %d but got %d", want, got)
+	field, err := NewAsteroidField(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := field.Vaporize(ex.best, 0)
+	if len(order) != len(field.Asteroids)-1 {
+		t.Fatalf("want %d vaporized but got %d", len(field.Asteroids)-1, len(order))
+	}
+	for _, tt := range []struct {
+		nth  int
+		want image.Point
+	}{
+		{1, image.Point{11, 12}},
+		{2, image.Point{12, 1}},
+		{3, image.Point{12, 2}},
+		{10, image.Point{12, 8}},
+		{20, image.Point{16, 0}},
+		{50, image.Point{16, 9}},
+		{100, image.Point{10, 16}},
+		{199, image.Point{9, 6}},
+		{200, image.Point{8, 2}},
+		{201, image.Point{10, 9}},
+		{299, image.Point{11, 1}},
+	} {
+		if got := order[tt.nth-1].Asteroid; tt.want != got {
+			t.Fatalf("#%d: want %v but got %v", tt.nth, tt.want, got)
+		}
+	}
+
+	// the first rotation hits exactly the asteroids the station can see
+	rotation, first := 1, 0
+	for _, v := range order {
+		if v.Rotation < rotation {
+			t.Fatalf("%v: rotation %d after %d", v.Asteroid, v.Rotation, rotation)
+		}
+		rotation = v.Rotation
+		if v.Rotation == 1 {
+			first++
+		}
+	}
+	if first != ex.bestCount {
+		t.Fatalf("want %d in first rotation but got %d", ex.bestCount, first)
 	}
 }
 
-func TestDay10Part2(t *testing.T) {
-	buf := fileFromFilename(t, filename, 10)
-	want := uint(1309)
-	got := Day10(buf, false)
-	if want != got {
+func TestVaporizeStation(t *testing.T) {
+	buf, err := os.ReadFile("testdata/day10_part2_example1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, err := NewAsteroidField(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (image.Point{8, 3}); !field.HasStation || want != field.Station {
+		t.Fatalf("want station at %v but got %v", want, field.Station)
+	}
+	order := field.Vaporize(field.Station, 0)
+	want := []Vaporization{
+		{image.Point{8, 1}, 1, 0},
+		{image.Point{9, 0}, 1, 18.43494882292201},
+		{image.Point{9, 1}, 1, 26.56505117707799},
+		{image.Point{10, 0}, 1, 33.690067525979785},
+		{image.Point{9, 2}, 1, 45},
+	}
+	if !slices.Equal(want, order[:len(want)]) {
+		t.Fatalf("want %v but got %v", want, order[:len(want)])
+	}
+	// the last one is behind the station, in the third turn
+	if want := (Vaporization{image.Point{14, 3}, 3, 90}); want != order[len(order)-1] {
+		t.Fatalf("want last %v but got %v", want, order[len(order)-1])
+	}
+
+	// start pointing down, at the asteroid below, and on an empty cell
+	order = field.Vaporize(image.Point{2, 1}, 180)
+	if want := (Vaporization{image.Point{2, 3}, 1, 180}); want != order[0] {
+		t.Fatalf("want first %v but got %v", want, order[0])
+	}
+	if got := len(order); got != len(field.Asteroids) {
+		t.Fatalf("want all %d asteroids vaporized but got %d", len(field.Asteroids), got)
+	}
+}
+
+// TestDay10MarkedStation answers part 1 for the station marked 'X', which
+// sees 1 asteroid, not for the best location, which sees 2.
+func TestDay10MarkedStation(t *testing.T) {
+	got, err := Day10([]byte("X#.#\n"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint(1); want != got {
 		t.Fatalf("want %d but got %d", want, got)
 	}
+	frames, err := Visualize(10, []byte("X#.#\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ink := frames[0].At(0, 0); ink != 2 {
+		t.Fatalf("want station drawn at 0,0 but got ink %d", ink)
+	}
 }
 
-func BenchmarkDay10Part2(b *testing.B) {
-	buf := fileFromFilename(b, filename, 10)
-	for b.Loop() {
-		_ = Day10(buf, false)
+func TestAsteroidVisibility(t *testing.T) {
+	buf := fileFromFilename(t, example1Filename, 10)
+	field, err := NewAsteroidField(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// counts as given in the puzzle
+	want := []int{7, 7, 6, 7, 7, 7, 5, 7, 8, 7}
+	if got := field.Visibility(); !slices.Equal(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+
+	var sb strings.Builder
+	if err := field.Heatmap().WriteASCII(&sb); err != nil {
+		t.Fatal(err)
+	}
+	heatmap := ".6..6\n.....\n36660\n....6\n...96\n"
+	if sb.String() != heatmap {
+		t.Fatalf("want heatmap\n%s\nbut got\n%s", heatmap, sb.String())
 	}
 }
+
+func TestDay10Part2(t *testing.T) {
+	testSolver(t, 10, filename, false, Day10, 1309)
+}
+
+func BenchmarkDay10Part2(b *testing.B) {
+	benchSolver(b, 10, false, Day10)
+}
+
+func TestDay10Errors(t *testing.T) {
+	testErrors(t, fromString(Day10), []errorCase[string]{
+		{"empty", "...\n...\n", true, "no asteroids"},
+		{"unexpected", "..#\n.o.\n", true, "line 2: unexpected 'o'"},
+		{"two stations", "X.#\n..X\n", true, "line 2: second station"},
+		{"too few", ".#.\n##.\n", false, "want at least 201 asteroids but got 3"},
+	})
+}
@@ -1,9 +1,10 @@
 package adventofcode2019
 
 import (
-	"bytes"
 	"fmt"
 	"image"
+
+	"gitlab.com/jhinrichsen/adventofcode2019/internal/ocr"
 )
 
 const (
@@ -35,30 +36,45 @@ func (a registrationID) dim() (min, max image.Point) {
 	return image.Point{X: minX - 2, Y: minY - 2}, image.Point{X: maxX + 2, Y: maxY + 2}
 }
 
-// pbm creates an image in portable bitmap format
-func (a registrationID) pbm() []byte {
-	var buf bytes.Buffer
-	fmt.Fprintln(&buf, "P1")
+// frame draws the hull with a margin, white panels as '#'.
+func (a registrationID) frame() *Frame {
 	min, max := a.dim()
-	fmt.Fprintf(&buf, "%d %d\n", max.X-min.X, max.Y-min.Y)
-	for y := min.Y; y < max.Y; y++ {
-		for x := min.X; x < max.X; x++ {
-			var pbmCol int
-			if b, ok := a[image.Point{X: x, Y: y}]; ok && b {
-				pbmCol = colorWhite
-			}
-			fmt.Fprintf(&buf, "%d", pbmCol)
+	f := NewFrame(max.X-min.X, max.Y-min.Y, []Ink{{' ', black}, {'#', white}})
+	for p, white := range a {
+		if white {
+			f.Set(p.X-min.X, p.Y-min.Y, 1)
 		}
-		buf.WriteString("\n")
 	}
-	return buf.Bytes()
+	return f
 }
 
-// Day11 runs the hull painting robot
-func Day11(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+// day11Frames draws the registration identifier painted in part 2.
+func day11Frames(program []byte) ([]*Frame, error) {
+	ic, err := NewIntcode(program)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	panels, err := runRobot(ic, colorWhite)
+	if err != nil {
+		return nil, err
+	}
+	return []*Frame{panels.frame()}, nil
+}
+
+// text reads the letters painted white.
+func (a registrationID) text() (string, error) {
+	min, max := a.dim()
+	return ocr.ReadText(max.X-min.X, max.Y-min.Y, func(x, y int) bool {
+		return a[image.Point{X: min.X + x, Y: min.Y + y}]
+	})
+}
+
+// Day11 runs the hull painting robot. Part 1 returns the number of panels
+// painted at least once, part 2 the registration identifier.
+func Day11(program []byte, part1 bool) (Answer, error) {
+	ic, err := NewIntcode(program)
+	if err != nil {
+		return Answer{}, err
 	}
 
 	var initialColor int
@@ -66,56 +82,51 @@ func Day11(program []byte, part1 bool) (uint, error) {
 		initialColor = colorWhite
 	}
 
-	panels := runRobot(ic, initialColor)
+	panels, err := runRobot(ic, initialColor)
+	if err != nil {
+		return Answer{}, err
+	}
 
 	if part1 {
-		return uint(len(panels)), nil
+		return Answer{Number: uint(len(panels))}, nil
 	}
-	// Part 2 returns a checksum of the image for testing
-	return uint(len(panels.pbm())), nil
+	text, err := panels.text()
+	return Answer{Text: text}, err
 }
 
-func runRobot(ic *intcode, initialColor int) registrationID {
+func runRobot(ic *Intcode, initialColor int) (registrationID, error) {
 	panels := make(registrationID)
 	position := image.Point{X: 0, Y: 0}
 	direction := image.Point{X: 0, Y: -1} // facing up
 
 	currentColor := initialColor
-	outputCount := 0
-	var paintColor int
-
 	for {
-		state := ic.Step()
-		switch state {
-		case needsInput:
-			ic.Input(currentColor)
-		case hasOutput:
-			if outputCount%2 == 0 {
-				// First output: color to paint
-				paintColor = ic.Output()
-			} else {
-				// Second output: turn direction
-				turn := ic.Output()
-				panels[position] = paintColor == colorWhite
-
-				// Turn and move
-				if turn == 0 { // left
-					direction = image.Point{X: -direction.Y, Y: direction.X}
-				} else { // right
-					direction = image.Point{X: direction.Y, Y: -direction.X}
-				}
-				position = position.Add(direction)
-
-				// Get color of new position
-				if panels[position] {
-					currentColor = colorWhite
-				} else {
-					currentColor = colorBlack
-				}
+		// Each input yields two outputs: color to paint, turn direction
+		outputs, state, err := ic.RunUntilInput(currentColor)
+		if err != nil {
+			return nil, fmt.Errorf("robot at %d,%d: %w", position.X, position.Y, err)
+		}
+		for i := 0; i+1 < len(outputs); i += 2 {
+			paintColor, turn := outputs[i], outputs[i+1]
+			panels[position] = paintColor == colorWhite
+
+			// Turn and move, y axis points down
+			if turn == 0 { // left
+				direction = image.Point{X: direction.Y, Y: -direction.X}
+			} else { // right
+				direction = image.Point{X: -direction.Y, Y: direction.X}
 			}
-			outputCount++
-		case halted:
-			return panels
+			position = position.Add(direction)
+		}
+		if state != NeedsInput {
+			return panels, nil
+		}
+
+		// Get color of new position
+		if panels[position] {
+			currentColor = colorWhite
+		} else {
+			currentColor = colorBlack
 		}
 	}
 }
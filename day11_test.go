@@ -3,19 +3,11 @@ package adventofcode2019
 import "testing"
 
 func TestDay11Part1(t *testing.T) {
-	testSolver(t, 11, filename, true, Day11, uint(2343))
+	testSolver(t, 11, filename, true, Day11, Answer{Number: 2343})
 }
 
 func TestDay11Part2(t *testing.T) {
-	// Part 2 returns len(pbm) as checksum - value determined from initial run
-	buf := fileFromFilename(t, filename, 11)
-	got, err := Day11(buf, false)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if got == 0 {
-		t.Fatal("expected non-zero result")
-	}
+	testSolver(t, 11, filename, false, Day11, Answer{Text: "JFBERBUH"})
 }
 
 func BenchmarkDay11Part1(b *testing.B) {
@@ -31,3 +23,10 @@ func BenchmarkDay11Part2(b *testing.B) {
 		_, _ = Day11(buf, false)
 	}
 }
+
+func TestDay11Errors(t *testing.T) {
+	testErrors(t, fromString(Day11), []errorCase[string]{
+		{"fault part 1", "42,0,0,0", true, "robot at 0,0: illegal opcode 42 at address 0"},
+		{"fault part 2", "42,0,0,0", false, "robot at 0,0: illegal opcode 42 at address 0"},
+	})
+}
@@ -2,6 +2,7 @@ package adventofcode2019
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -169,3 +170,58 @@ func (a universe) energy() int {
 	}
 	return sum
 }
+
+// parseMoons reads the initial moon positions, one moon per line in
+// format <x=-1, y=0, z=2>.
+func parseMoons(lines []string) (universe, error) {
+	var u universe
+	isNumeric := func(c byte) bool {
+		return c == '-' || c >= '0' && c <= '9'
+	}
+	if len(lines) != moons {
+		return u, fmt.Errorf("want %d moons but got %d lines", moons, len(lines))
+	}
+	for j, line := range lines {
+		buf := []byte(line)
+		idx := 0
+		l := len(line)
+		nextNum := func() (int, error) {
+			// skip any non-numeric characters
+			for idx < l && !isNumeric(buf[idx]) {
+				idx++
+			}
+			from := idx
+			for idx < l && isNumeric(buf[idx]) {
+				idx++
+			}
+			n, err := strconv.Atoi(string(buf[from:idx]))
+			return int(n), err
+		}
+		for i := range dims {
+			var err error
+			u.moons[i][j].pos, err = nextNum()
+			if err != nil {
+				return u, fmt.Errorf("line %d: %w", j+1, err)
+			}
+		}
+	}
+	return u, nil
+}
+
+// Day12 returns the total energy after 1000 steps (part 1), or the number of
+// steps until the moons return to a previous state (part 2).
+func Day12(lines []string, part1 bool) (uint, error) {
+	u, err := parseMoons(lines)
+	if err != nil {
+		return 0, err
+	}
+	if !part1 {
+		return uint(u.cycle()), nil
+	}
+	for range 1000 {
+		for dim := range dims {
+			u.step(dim)
+		}
+	}
+	return uint(u.energy()), nil
+}
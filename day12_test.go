@@ -2,7 +2,6 @@ package adventofcode2019
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"testing"
 	"unsafe"
@@ -85,7 +84,7 @@ func day12FromFile(filename string) (universe, error) {
 	if err != nil {
 		return universe{}, err
 	}
-	return parseUsingParser(input)
+	return parseMoons(input)
 }
 
 func TestDay12Example1Timeline(t *testing.T) {
@@ -144,7 +143,7 @@ func EnergyFromFile(filename string, steps int) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	u, err := parseUsingParser(input)
+	u, err := parseMoons(input)
 	if err != nil {
 		return 0, err
 	}
@@ -194,7 +193,7 @@ func TestDay12Parse(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	u, err := parseUsingParser(lines)
+	u, err := parseMoons(lines)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -205,49 +204,17 @@ func TestDay12Parse(t *testing.T) {
 	}
 }
 
-func parseUsingParser(lines []string) (universe, error) {
-	var u universe
-	isNumeric := func(c byte) bool {
-		return c == '-' || c >= '0' && c <= '9'
-	}
-	for j, line := range lines {
-		buf := []byte(line)
-		idx := 0
-		l := len(line)
-		nextNum := func() (int, error) {
-			// skip any non-numeric characters
-			for !isNumeric(buf[idx]) && idx < l {
-				idx++
-			}
-			from := idx
-			for isNumeric(buf[idx]) && idx < l {
-				idx++
-			}
-			n, err := strconv.Atoi(string(buf[from:idx]))
-			return int(n), err
-		}
-		for i := range dims {
-			var err error
-			u.moons[i][j].pos, err = nextNum()
-			if err != nil {
-				return u, err
-			}
-		}
-	}
-	return u, nil
-}
-
 func BenchmarkParseUsingParser(b *testing.B) {
 	lines := testLinesFromFilename(b, filename(12))
 	for range b.N {
-		_, _ = parseUsingParser(lines)
+		_, _ = parseMoons(lines)
 	}
 }
 
 func BenchmarkDay12Example2(b *testing.B) {
 	input := testLinesFromFilename(b, filename(12))
 	for range b.N {
-		u, err := parseUsingParser(input)
+		u, err := parseMoons(input)
 		if err != nil {
 			continue
 		}
@@ -308,7 +275,7 @@ func TestDay12Sizeof(t *testing.T) {
 func BenchmarkDay12Part1(b *testing.B) {
 	lines := testLinesFromFilename(b, filename(12))
 	for range b.N {
-		u, err := parseUsingParser(lines)
+		u, err := parseMoons(lines)
 		if err != nil {
 			continue
 		}
@@ -324,10 +291,38 @@ func BenchmarkDay12Part1(b *testing.B) {
 func BenchmarkDay12Part2(b *testing.B) {
 	lines := testLinesFromFilename(b, filename(12))
 	for range b.N {
-		u, err := parseUsingParser(lines)
+		u, err := parseMoons(lines)
 		if err != nil {
 			continue
 		}
 		_ = u.cycle()
 	}
 }
+
+func TestDay12Solve(t *testing.T) {
+	lines := testLinesFromFilename(t, filename(12))
+	for _, tt := range []struct {
+		part1 bool
+		want  uint
+	}{
+		{true, 7471},
+		{false, 376243355967784},
+	} {
+		got, err := Day12(lines, tt.part1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != got {
+			t.Fatalf("part1=%t: want %d but got %d", tt.part1, tt.want, got)
+		}
+	}
+}
+
+func TestDay12Errors(t *testing.T) {
+	moons := []string{"<x=-1, y=0, z=2>", "<x=2, y=-10, z=-7>", "<x=4, y=-8, z=8>"}
+	testErrors(t, Day12, []errorCase[[]string]{
+		{"moons", moons, true, "want 4 moons but got 3 lines"},
+		{"coordinates", append(moons, "<x=3, y=5>"), true, `line 4: strconv.Atoi: parsing "": invalid syntax`},
+		{"number", append(moons, "<x=3, y=5, z=-->"), true, `line 4: strconv.Atoi: parsing "--": invalid syntax`},
+	})
+}
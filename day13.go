@@ -1,5 +1,10 @@
 package adventofcode2019
 
+import (
+	"fmt"
+	"image"
+)
+
 const (
 	blockTile  = 2
 	paddleTile = 3
@@ -8,86 +13,109 @@ const (
 
 // Day13 runs the arcade game
 func Day13(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
 
+	var n int
 	if part1 {
-		return uint(day13Part1(ic)), nil
+		n, err = day13Part1(ic)
+	} else {
+		n, err = day13Part2(ic, nil)
 	}
-	return uint(day13Part2(ic)), nil
+	return uint(n), err
 }
 
-func day13Part1(ic *intcode) int {
+func day13Part1(ic *Intcode) (int, error) {
 	blocks := 0
-	outputIdx := 0
-	var x, y int
-
-	for {
-		state := ic.Step()
-		switch state {
-		case hasOutput:
-			val := ic.Output()
-			switch outputIdx % 3 {
-			case 0:
-				x = val
-			case 1:
-				y = val
-			case 2:
-				_ = x // suppress unused
-				_ = y
-				if val == blockTile {
-					blocks++
-				}
-			}
-			outputIdx++
-		case halted:
-			return blocks
+	outputs, _, err := ic.RunUntilInput()
+	if err != nil {
+		return 0, fmt.Errorf("arcade: %w", err)
+	}
+	// Output triples: x, y, tile
+	for i := 2; i < len(outputs); i += 3 {
+		if outputs[i] == blockTile {
+			blocks++
 		}
 	}
+	return blocks, nil
 }
 
-func day13Part2(ic *intcode) int {
+// day13Part2 plays the game and returns the final score. draw, if not nil,
+// is called for every tile and score update.
+func day13Part2(ic *Intcode, draw func(x, y, val int)) (int, error) {
 	// Play for free
 	ic.SetMem(0, 2)
 
-	outputIdx := 0
-	var x, y int
 	var ballX, paddleX int
 	score := 0
 
+	var inputs []int
+	var pending []int // incomplete output triple
 	for {
-		state := ic.Step()
-		switch state {
-		case needsInput:
-			// Move paddle towards ball
-			joystick := 0
-			if paddleX < ballX {
-				joystick = 1
-			} else if paddleX > ballX {
-				joystick = -1
+		outputs, state, err := ic.RunUntilInput(inputs...)
+		if err != nil {
+			return 0, fmt.Errorf("arcade at score %d: %w", score, err)
+		}
+		pending = append(pending, outputs...)
+		i := 0
+		for ; i+2 < len(pending); i += 3 {
+			x, y, val := pending[i], pending[i+1], pending[i+2]
+			if draw != nil {
+				draw(x, y, val)
 			}
-			ic.Input(joystick)
-		case hasOutput:
-			val := ic.Output()
-			switch outputIdx % 3 {
-			case 0:
-				x = val
-			case 1:
-				y = val
-			case 2:
-				if x == -1 && y == 0 {
-					score = val
-				} else if val == ballTile {
-					ballX = x
-				} else if val == paddleTile {
-					paddleX = x
-				}
+			if x == -1 && y == 0 {
+				score = val
+			} else if val == ballTile {
+				ballX = x
+			} else if val == paddleTile {
+				paddleX = x
 			}
-			outputIdx++
-		case halted:
-			return score
 		}
+		pending = append(pending[:0], pending[i:]...)
+		if state != NeedsInput {
+			return score, nil
+		}
+
+		// Move paddle towards ball
+		joystick := 0
+		if paddleX < ballX {
+			joystick = 1
+		} else if paddleX > ballX {
+			joystick = -1
+		}
+		inputs = append(inputs[:0], joystick)
+	}
+}
+
+// day13Frames plays the game and draws the screen each time the score
+// changes, the first frame is the initial screen.
+func day13Frames(program []byte) ([]*Frame, error) {
+	ic, err := NewIntcode(program)
+	if err != nil {
+		return nil, err
+	}
+	palette := []Ink{{' ', black}, {'|', gray}, {'#', blue}, {'-', yellow}, {'o', white}}
+	tiles := make(map[image.Point]uint8)
+	var frames []*Frame
+	snapshot := func() {
+		f, offset := pointsFrame(tiles, palette)
+		for p, tile := range tiles {
+			f.Set(p.X+offset.X, p.Y+offset.Y, tile)
+		}
+		frames = append(frames, f)
+	}
+	_, err = day13Part2(ic, func(x, y, val int) {
+		if x == -1 && y == 0 {
+			snapshot()
+			return
+		}
+		tiles[image.Point{X: x, Y: y}] = uint8(val)
+	})
+	if err != nil {
+		return nil, err
 	}
+	snapshot()
+	return frames, nil
 }
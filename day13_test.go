@@ -23,3 +23,11 @@ func BenchmarkDay13Part2(b *testing.B) {
 		_, _ = Day13(buf, false)
 	}
 }
+
+func TestDay13Errors(t *testing.T) {
+	testErrors(t, fromString(Day13), []errorCase[string]{
+		{"fault part 1", "42,0,0,0", true, "arcade: illegal opcode 42 at address 0"},
+		{"fault part 2", "1,-5,0,0", false,
+			"arcade at score 0: instruction 2 at address 0: address -5 of parameter 1 out of memory"},
+	})
+}
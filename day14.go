@@ -1,6 +1,7 @@
 package adventofcode2019
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -21,11 +22,13 @@ type reaction struct {
 // reactionMap maps chemical names to their reactions
 type reactionMap map[string]reaction
 
-// parseReactions parses the input lines into a reaction map
+// parseReactions parses the input lines into a reaction map, and checks that
+// every chemical except ORE is produced by exactly one reaction.
 func parseReactions(lines []string) (reactionMap, error) {
 	reactions := make(reactionMap)
+	lineNumbers := make(map[string]int)
 
-	for _, line := range lines {
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -33,13 +36,17 @@ func parseReactions(lines []string) (reactionMap, error) {
 
 		parts := strings.Split(line, "=>")
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid reaction format: %s", line)
+			return nil, fmt.Errorf("line %d: invalid reaction format: %s", i+1, line)
 		}
 
 		// Parse output
 		output, err := parseChemical(strings.TrimSpace(parts[1]))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if _, ok := reactions[output.Name]; ok {
+			return nil, fmt.Errorf("line %d: %s is already produced in line %d",
+				i+1, output.Name, lineNumbers[output.Name])
 		}
 
 		// Parse inputs
@@ -48,7 +55,7 @@ func parseReactions(lines []string) (reactionMap, error) {
 		for _, inputStr := range inputStrs {
 			input, err := parseChemical(strings.TrimSpace(inputStr))
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
 			}
 			inputs = append(inputs, input)
 		}
@@ -57,6 +64,43 @@ func parseReactions(lines []string) (reactionMap, error) {
 			Inputs: inputs,
 			Output: output,
 		}
+		lineNumbers[output.Name] = i + 1
+	}
+
+	// Every input must be produced by some reaction, without cycles
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(reactions))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("line %d: reactions for %s form a cycle", lineNumbers[name], name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		for _, input := range reactions[name].Inputs {
+			if input.Name == "ORE" {
+				continue
+			}
+			if _, ok := reactions[input.Name]; !ok {
+				return fmt.Errorf("line %d: no reaction produces %s", lineNumbers[name], input.Name)
+			}
+			if err := visit(input.Name); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+	for name := range reactions {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
 	}
 
 	return reactions, nil
@@ -82,6 +126,9 @@ func parseChemical(s string) (chemical, error) {
 	if err != nil {
 		return chemical{}, err
 	}
+	if quantity == 0 {
+		return chemical{}, fmt.Errorf("zero quantity: %s", s)
+	}
 	// Skip whitespace between quantity and name
 	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
 		i++
@@ -91,6 +138,9 @@ func parseChemical(s string) (chemical, error) {
 	for end > i && (s[end-1] == ' ' || s[end-1] == '\t') {
 		end--
 	}
+	if i == end {
+		return chemical{}, fmt.Errorf("missing chemical name: %s", s)
+	}
 	return chemical{
 		Name:     s[i:end],
 		Quantity: uint(quantity),
@@ -170,15 +220,18 @@ func calculateOreWithMaps(reactions reactionMap, target string, targetQty uint,
 
 // Day14 calculates either minimum ORE for 1 FUEL (part1) or
 // maximum FUEL from 1 trillion ORE (part2)
-func Day14(lines []string, part1 bool) uint {
+func Day14(lines []string, part1 bool) (uint, error) {
 	reactions, err := parseReactions(lines)
 	if err != nil {
-		return 0
+		return 0, err
+	}
+	if _, ok := reactions["FUEL"]; !ok {
+		return 0, errors.New("no reaction produces FUEL")
 	}
 
 	if part1 {
 		// Part 1: minimum ORE needed to produce 1 FUEL
-		return calculateOre(reactions, "FUEL", 1)
+		return calculateOre(reactions, "FUEL", 1), nil
 	}
 
 	// Part 2: maximum FUEL that can be produced from 1 trillion ORE
@@ -209,5 +262,5 @@ func Day14(lines []string, part1 bool) uint {
 		}
 	}
 
-	return result
+	return result, nil
 }
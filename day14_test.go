@@ -106,7 +106,24 @@ func ExampleDay14() {
 		"7 A, 1 D => 1 E",
 		"7 A, 1 E => 1 FUEL",
 	}
-	ore := Day14(input, true)
+	ore, err := Day14(input, true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println(ore)
 	// Output: 31
 }
+
+func TestDay14Errors(t *testing.T) {
+	testErrors(t, Day14, []errorCase[[]string]{
+		{"format", []string{"10 ORE -> 1 FUEL"}, true, "line 1: invalid reaction format: 10 ORE -> 1 FUEL"},
+		{"quantity", []string{"1 ORE => 1 A", "ORE => 1 FUEL"}, true, "line 2: invalid chemical format: ORE"},
+		{"zero", []string{"0 ORE => 1 FUEL"}, true, "line 1: zero quantity: 0 ORE"},
+		{"name", []string{"1 ORE => 1"}, true, "line 1: missing chemical name: 1"},
+		{"duplicate", []string{"1 ORE => 1 A", "2 ORE => 1 A"}, true, "line 2: A is already produced in line 1"},
+		{"undefined", []string{"1 ORE => 1 A", "1 B => 1 FUEL"}, true, "line 2: no reaction produces B"},
+		{"cycle", []string{"1 A => 1 A"}, true, "line 1: reactions for A form a cycle"},
+		{"no fuel", []string{"1 ORE => 1 A"}, true, "no reaction produces FUEL"},
+	})
+}
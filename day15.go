@@ -1,37 +1,78 @@
 package adventofcode2019
 
-import "image"
+import (
+	"fmt"
+	"image"
+)
+
+// droidDirections are the movement commands with their offset and the
+// command that moves back.
+var droidDirections = []struct {
+	cmd, dx, dy, reverse int
+}{
+	{1, 0, -1, 2}, // north
+	{2, 0, 1, 1},  // south
+	{3, -1, 0, 4}, // west
+	{4, 1, 0, 3},  // east
+}
+
+// shipMap is the area explored by the repair droid.
+type shipMap struct {
+	grid        map[image.Point]int // 0=wall, 1=open, 2=oxygen
+	oxygenPos   image.Point
+	oxygenSteps uint
+}
 
 // Day15 finds the minimum steps to the oxygen system (part1)
 // or time to fill with oxygen (part2)
 func Day15(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
+	if err != nil {
+		return 0, err
+	}
+	ship, err := exploreShip(ic, part1)
 	if err != nil {
 		return 0, err
 	}
+	if part1 {
+		return ship.oxygenSteps, nil
+	}
+	return ship.fillTime(), nil
+}
 
-	// Direction: cmd, dx, dy, reverse cmd
-	directions := []struct {
-		cmd, dx, dy, reverse int
-	}{
-		{1, 0, -1, 2}, // north
-		{2, 0, 1, 1},  // south
-		{3, -1, 0, 4}, // west
-		{4, 1, 0, 3},  // east
+// day15Frames draws the explored maze with the start and the oxygen system.
+func day15Frames(program []byte) ([]*Frame, error) {
+	ic, err := NewIntcode(program)
+	if err != nil {
+		return nil, err
+	}
+	ship, err := exploreShip(ic, false)
+	if err != nil {
+		return nil, err
+	}
+	palette := []Ink{{' ', black}, {'#', gray}, {'.', white}, {'O', blue}, {'D', red}}
+	f, offset := pointsFrame(ship.grid, palette)
+	for p, tile := range ship.grid {
+		p = p.Add(offset)
+		f.Set(p.X, p.Y, uint8(tile+1))
 	}
+	// the droid starts at 0,0
+	f.Set(offset.X, offset.Y, 4)
+	return []*Frame{f}, nil
+}
 
-	sendCommand := func(cmd int) int {
-		for {
-			state := ic.Step()
-			switch state {
-			case needsInput:
-				ic.Input(cmd)
-			case hasOutput:
-				return ic.Output()
-			case halted:
-				return -1
-			}
+// exploreShip moves the droid until it knows every reachable cell, or until
+// it finds the oxygen system if stopAtOxygen is set.
+func exploreShip(ic *Intcode, stopAtOxygen bool) (*shipMap, error) {
+	sendCommand := func(cmd int) (int, error) {
+		outputs, _, err := ic.RunUntilInput(cmd)
+		if err != nil {
+			return 0, fmt.Errorf("droid command %d: %w", cmd, err)
+		}
+		if len(outputs) != 1 || outputs[0] < 0 || outputs[0] > 2 {
+			return 0, fmt.Errorf("droid command %d: want status 0, 1 or 2 but got %v", cmd, outputs)
 		}
+		return outputs[0], nil
 	}
 
 	// Explore entire maze, track distance to each cell
@@ -41,7 +82,8 @@ func Day15(program []byte, part1 bool) (uint, error) {
 	}
 
 	dist := make(map[image.Point]uint)
-	grid := make(map[image.Point]int) // 0=wall, 1=open, 2=oxygen
+	ship := &shipMap{grid: make(map[image.Point]int)}
+	grid := ship.grid
 
 	start := image.Point{}
 	dist[start] = 0
@@ -49,13 +91,11 @@ func Day15(program []byte, part1 bool) (uint, error) {
 
 	queue := []cell{{pos: start, steps: 0}}
 	currentPos := start
-	var oxygenPos image.Point
-	var oxygenSteps uint
 
 	// BFS pathfinding to move droid
-	moveTo := func(from, to image.Point) {
+	moveTo := func(from, to image.Point) error {
 		if from == to {
-			return
+			return nil
 		}
 		type node struct {
 			pos image.Point
@@ -70,7 +110,7 @@ func Day15(program []byte, part1 bool) (uint, error) {
 			if cur == to {
 				break
 			}
-			for _, dir := range directions {
+			for _, dir := range droidDirections {
 				next := image.Point{X: cur.X + dir.dx, Y: cur.Y + dir.dy}
 				if _, seen := parent[next]; seen {
 					continue
@@ -92,8 +132,11 @@ func Day15(program []byte, part1 bool) (uint, error) {
 		}
 		// Execute in reverse
 		for i := len(path) - 1; i >= 0; i-- {
-			sendCommand(path[i])
+			if _, err := sendCommand(path[i]); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
 
 	// Explore
@@ -101,17 +144,22 @@ func Day15(program []byte, part1 bool) (uint, error) {
 		cur := queue[head]
 
 		if currentPos != cur.pos {
-			moveTo(currentPos, cur.pos)
+			if err := moveTo(currentPos, cur.pos); err != nil {
+				return nil, err
+			}
 			currentPos = cur.pos
 		}
 
-		for _, dir := range directions {
+		for _, dir := range droidDirections {
 			next := image.Point{X: cur.pos.X + dir.dx, Y: cur.pos.Y + dir.dy}
 			if _, seen := dist[next]; seen {
 				continue
 			}
 
-			status := sendCommand(dir.cmd)
+			status, err := sendCommand(dir.cmd)
+			if err != nil {
+				return nil, err
+			}
 			dist[next] = cur.steps + 1
 			grid[next] = status
 
@@ -120,33 +168,40 @@ func Day15(program []byte, part1 bool) (uint, error) {
 			}
 
 			if status == 2 { // oxygen
-				oxygenPos = next
-				oxygenSteps = cur.steps + 1
-				if part1 {
-					return oxygenSteps, nil
+				ship.oxygenPos = next
+				ship.oxygenSteps = cur.steps + 1
+				if stopAtOxygen {
+					return ship, nil
 				}
 			}
 
 			queue = append(queue, cell{pos: next, steps: cur.steps + 1})
-			sendCommand(dir.reverse) // move back
+			// move back
+			if _, err := sendCommand(dir.reverse); err != nil {
+				return nil, err
+			}
 		}
 	}
+	return ship, nil
+}
 
-	// Part 2: BFS from oxygen position to find max fill time
+// fillTime returns the minutes until oxygen fills every open cell.
+func (a *shipMap) fillTime() uint {
+	// BFS from oxygen position to find max fill time
 	filled := make(map[image.Point]bool)
-	filled[oxygenPos] = true
-	front := []image.Point{oxygenPos}
+	filled[a.oxygenPos] = true
+	front := []image.Point{a.oxygenPos}
 	var minutes uint
 
 	for len(front) > 0 {
 		var nextFront []image.Point
 		for _, pos := range front {
-			for _, dir := range directions {
+			for _, dir := range droidDirections {
 				next := image.Point{X: pos.X + dir.dx, Y: pos.Y + dir.dy}
 				if filled[next] {
 					continue
 				}
-				if tile := grid[next]; tile == 0 {
+				if tile := a.grid[next]; tile == 0 {
 					continue
 				}
 				filled[next] = true
@@ -159,5 +214,5 @@ func Day15(program []byte, part1 bool) (uint, error) {
 		front = nextFront
 	}
 
-	return minutes, nil
+	return minutes
 }
@@ -23,3 +23,10 @@ func BenchmarkDay15Part2(b *testing.B) {
 		_, _ = Day15(buf, false)
 	}
 }
+
+func TestDay15Errors(t *testing.T) {
+	testErrors(t, fromString(Day15), []errorCase[string]{
+		{"fault", "42,0,0,0", true, "droid command 1: illegal opcode 42 at address 0"},
+		{"status", "3,0,104,7,99", false, "droid command 1: want status 0, 1 or 2 but got [7]"},
+	})
+}
@@ -2,16 +2,26 @@ package adventofcode2019
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 )
 
 // Day16 applies Flawed Frequency Transmission (FFT) algorithm
 // Part 1: Returns first 8 digits after 100 phases
-// Part 2: TBD
-func Day16(input []byte, part1 bool) uint {
+// Part 2: Returns the 8 digit message at the offset of the repeated signal
+func Day16(input []byte, part1 bool) (uint, error) {
 	input = bytes.TrimSpace(input)
+	if len(input) == 0 {
+		return 0, errors.New("empty signal")
+	}
+	for i, b := range input {
+		if b < '0' || b > '9' {
+			return 0, fmt.Errorf("want digit but got %q at position %d", b, i+1)
+		}
+	}
 
 	if part1 {
-		return fftPart1(input)
+		return fftPart1(input), nil
 	}
 	return fftPart2(input)
 }
@@ -79,9 +89,12 @@ func fftPart1(input []byte) uint {
 	return result
 }
 
-func fftPart2(input []byte) uint {
+func fftPart2(input []byte) (uint, error) {
 	// Parse digits inline
 	n := len(input)
+	if n < 7 {
+		return 0, fmt.Errorf("want at least 7 digits for the message offset but got %d", n)
+	}
 
 	// Extract offset from first 7 digits
 	offset := 0
@@ -96,10 +109,11 @@ func fftPart2(input []byte) uint {
 	// Each output digit is sum of all digits from that position to end (mod 10)
 	// We only need to keep track of digits from offset to end
 
-	if offset < repeatedLength/2 {
+	if offset < repeatedLength/2 || offset+8 > repeatedLength {
 		// Offset is in first half - would need full FFT (very slow)
 		// This shouldn't happen with valid puzzle inputs
-		return 0
+		return 0, fmt.Errorf("unsupported message offset %d, want second half of %d digits",
+			offset, repeatedLength)
 	}
 
 	// Build the relevant portion (from offset to end) using bytes
@@ -127,7 +141,7 @@ func fftPart2(input []byte) uint {
 		result = result*10 + uint(signal[i])
 	}
 
-	return result
+	return result, nil
 }
 
 // parseDigits converts byte string to slice of int digits
@@ -62,7 +62,10 @@ func TestDay16Part1(t *testing.T) {
 		t.Fatal(err)
 	}
 	const want = 59281788
-	got := Day16(prog, true)
+	got, err := Day16(prog, true)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if want != got {
 		t.Fatalf("want %v but got %v", want, got)
 	}
@@ -80,7 +83,10 @@ func TestDay16Part2Examples(t *testing.T) {
 
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("example%d", i+1), func(t *testing.T) {
-			got := fftPart2([]byte(tt.input))
+			got, err := fftPart2([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
 			if got != tt.want {
 				t.Fatalf("want %v but got %v", tt.want, got)
 			}
@@ -94,7 +100,10 @@ func TestDay16Part2(t *testing.T) {
 		t.Fatal(err)
 	}
 	const want = 96062868
-	got := Day16(prog, false)
+	got, err := Day16(prog, false)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if want != got {
 		t.Fatalf("want %v but got %v", want, got)
 	}
@@ -107,7 +116,7 @@ func BenchmarkDay16Part1(b *testing.B) {
 	}
 	b.ResetTimer()
 	for range b.N {
-		Day16(prog, true)
+		_, _ = Day16(prog, true)
 	}
 }
 
@@ -118,6 +127,15 @@ func BenchmarkDay16Part2(b *testing.B) {
 	}
 	b.ResetTimer()
 	for range b.N {
-		Day16(prog, false)
+		_, _ = Day16(prog, false)
 	}
 }
+
+func TestDay16Errors(t *testing.T) {
+	testErrors(t, fromString(Day16), []errorCase[string]{
+		{"empty", "\n", true, "empty signal"},
+		{"digit", "1234x678", true, "want digit but got 'x' at position 5"},
+		{"short", "123456", false, "want at least 7 digits for the message offset but got 6"},
+		{"first half", "12345678", false, "unsupported message offset 1234567, want second half of 80000 digits"},
+	})
+}
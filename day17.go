@@ -1,43 +1,82 @@
 package adventofcode2019
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Day17 analyzes scaffolding map from ASCII camera
 // Part 1: Sum of alignment parameters at intersections
 // Part 2: Collect dust by visiting all scaffold
 func Day17(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
 
 	if part1 {
-		return calculateAlignmentSum(ic), nil
+		return calculateAlignmentSum(ic)
 	}
-	return collectDust(ic), nil
+	return collectDust(ic)
 }
 
-func calculateAlignmentSum(ic *intcode) uint {
-	// Run the Intcode program to get ASCII output
+// cameraView runs the Intcode program and returns the lines of its ASCII
+// output.
+func cameraView(ic *Intcode) ([][]byte, error) {
 	var grid [][]byte
 	var row []byte
 
-	for {
-		state := ic.Step()
-		switch state {
-		case hasOutput:
-			ch := byte(ic.Output())
-			if ch == '\n' {
-				if len(row) > 0 {
-					grid = append(grid, row)
-					row = nil
-				}
-			} else {
-				row = append(row, ch)
+	outputs, state, err := ic.RunUntilInput()
+	if err != nil {
+		return nil, fmt.Errorf("camera: %w", err)
+	}
+	if state != Halted {
+		return nil, errors.New("camera: want program to halt but it asks for input")
+	}
+	for _, out := range outputs {
+		ch := byte(out)
+		if ch == '\n' {
+			if len(row) > 0 {
+				grid = append(grid, row)
+				row = nil
 			}
-		case halted:
-			goto done
+		} else {
+			row = append(row, ch)
 		}
 	}
-done:
+	return grid, nil
+}
+
+// day17Frames draws the scaffold and the vacuum robot.
+func day17Frames(program []byte) ([]*Frame, error) {
+	ic, err := NewIntcode(program)
+	if err != nil {
+		return nil, err
+	}
+	grid, err := cameraView(ic)
+	if err != nil {
+		return nil, err
+	}
+	palette := []Ink{{'.', black}, {'#', gray}, {'^', yellow}, {'X', red}}
+	f := gridFrame(grid, palette, func(b byte) uint8 {
+		switch b {
+		case '#':
+			return 1
+		case '^', 'v', '<', '>':
+			return 2
+		case 'X':
+			return 3
+		}
+		return 0
+	})
+	return []*Frame{f}, nil
+}
+
+func calculateAlignmentSum(ic *Intcode) (uint, error) {
+	grid, err := cameraView(ic)
+	if err != nil {
+		return 0, err
+	}
 
 	// Find intersections and calculate alignment parameters
 	sum := uint(0)
@@ -50,7 +89,7 @@ done:
 		}
 	}
 
-	return sum
+	return sum, nil
 }
 
 // isIntersection checks if position (x, y) is a scaffold intersection
@@ -73,7 +112,7 @@ func isIntersection(grid [][]byte, x, y int) bool {
 	return true
 }
 
-func collectDust(ic *intcode) uint {
+func collectDust(ic *Intcode) (uint, error) {
 	// Wake up the robot by changing address 0 from 1 to 2
 	ic.SetMem(0, 2)
 
@@ -84,25 +123,28 @@ func collectDust(ic *intcode) uint {
 	// Function C: L,12,L,12,L,10,R,10
 	// Video feed: n
 	commands := "A,A,B,C,B,C,B,A,C,A\nR,8,L,12,R,8\nL,10,L,10,R,8\nL,12,L,12,L,10,R,10\nn\n"
-	cmdIdx := 0
 
 	var lastOutput uint
-
-	for {
-		state := ic.Step()
-		switch state {
-		case needsInput:
-			if cmdIdx < len(commands) {
-				ic.Input(int(commands[cmdIdx]))
-				cmdIdx++
-			}
-		case hasOutput:
-			val := ic.Output()
-			if val > 255 {
-				lastOutput = uint(val)
-			}
-		case halted:
-			return lastOutput
+	outputs, state, err := ic.RunUntilInput(asciiInputs(commands)...)
+	if err != nil {
+		return 0, fmt.Errorf("vacuum robot: %w", err)
+	}
+	if state != Halted {
+		return 0, errors.New("vacuum robot: want program to halt but it asks for more input")
+	}
+	for _, val := range outputs {
+		if val > 255 {
+			lastOutput = uint(val)
 		}
 	}
+	return lastOutput, nil
+}
+
+// asciiInputs converts text into Intcode inputs, one per byte.
+func asciiInputs(s string) []int {
+	inputs := make([]int, len(s))
+	for i := range len(s) {
+		inputs[i] = int(s[i])
+	}
+	return inputs
 }
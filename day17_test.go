@@ -23,3 +23,13 @@ func BenchmarkDay17Part2(b *testing.B) {
 		_, _ = Day17(buf, false)
 	}
 }
+
+func TestDay17Errors(t *testing.T) {
+	testErrors(t, fromString(Day17), []errorCase[string]{
+		{"fault part 1", "42,0,0,0", true, "camera: illegal opcode 42 at address 0"},
+		{"fault part 2", "1,-5,0,0", false,
+			"vacuum robot: instruction 2 at address 0: address -5 of parameter 1 out of memory"},
+		{"input part 1", "3,0,99", true, "camera: want program to halt but it asks for input"},
+		{"input part 2", "1,0,0,9,3,9,1105,1,4", false, "vacuum robot: want program to halt but it asks for more input"},
+	})
+}
@@ -3,17 +3,35 @@ package adventofcode2019
 import (
 	"bytes"
 	"container/heap"
+	"errors"
+	"fmt"
 	"image"
 )
 
+// errKeysUnreachable is returned if no order of collecting all keys exists.
+var errKeysUnreachable = errors.New("cannot collect all keys")
+
 // Day18 solves the "Many-Worlds Interpretation" puzzle.
 // It finds the minimum number of steps to collect all keys in a maze with doors.
-func Day18(input []byte, part1 bool) uint {
-	maze := parseMaze(input)
+func Day18(input []byte, part1 bool) (uint, error) {
+	maze, err := parseMaze(input)
+	if err != nil {
+		return 0, err
+	}
+	var steps uint
 	if part1 {
-		return solvePart1(maze)
+		steps = solvePart1(maze)
+	} else {
+		cx, cy := maze.startX, maze.startY
+		if cx < 1 || cy < 1 || cx+1 >= maze.dimX || cy+1 >= maze.dimY {
+			return 0, fmt.Errorf("entrance at %d,%d too close to the border to split", cx, cy)
+		}
+		steps = solvePart2(maze)
 	}
-	return solvePart2(maze)
+	if steps == 0 && len(maze.keys) > 0 {
+		return 0, errKeysUnreachable
+	}
+	return steps, nil
 }
 
 type day18Puzzle struct {
@@ -26,13 +44,15 @@ type day18Puzzle struct {
 	robots4 [4]image.Point // For part 2
 }
 
-func parseMaze(input []byte) day18Puzzle {
-	lines := bytes.Split(bytes.TrimSpace(input), []byte{'\n'})
-	dimY := len(lines)
-	if dimY == 0 {
-		return day18Puzzle{}
+func parseMaze(input []byte) (day18Puzzle, error) {
+	input = bytes.TrimSpace(input)
+	if len(input) == 0 {
+		return day18Puzzle{}, errors.New("empty maze")
 	}
+	lines := bytes.Split(input, []byte{'\n'})
+	dimY := len(lines)
 	dimX := len(lines[0])
+	entrances := 0
 
 	maze := day18Puzzle{
 		grid: make([][]byte, dimY),
@@ -48,17 +68,26 @@ func parseMaze(input []byte) day18Puzzle {
 				cell := lines[y][x]
 				maze.grid[y][x] = cell
 
-				if cell == '@' {
+				switch {
+				case cell == '@':
 					maze.startX = x
 					maze.startY = y
-				} else if cell >= 'a' && cell <= 'z' {
+					entrances++
+				case cell >= 'a' && cell <= 'z':
 					maze.keys[cell] = true
+				case cell == '#' || cell == '.' || cell >= 'A' && cell <= 'Z':
+					// wall, open passage or door
+				default:
+					return maze, fmt.Errorf("line %d: unexpected %q at column %d", y+1, cell, x+1)
 				}
 			}
 		}
 	}
 
-	return maze
+	if entrances != 1 {
+		return maze, fmt.Errorf("want one entrance '@' but got %d", entrances)
+	}
+	return maze, nil
 }
 
 func solvePart1(maze day18Puzzle) uint {
@@ -409,3 +438,12 @@ func (h *day18Heap4) Pop() any {
 	*h = old[:n-1]
 	return x
 }
+
+// day18Frames draws the vault.
+func day18Frames(input []byte) ([]*Frame, error) {
+	maze, err := parseMaze(input)
+	if err != nil {
+		return nil, err
+	}
+	return []*Frame{gridFrame(maze.grid, mazePalette, mazeInk)}, nil
+}
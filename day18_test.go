@@ -12,21 +12,24 @@ func TestDay18Part1Examples(t *testing.T) {
 	for i, want := range wants {
 		example := uint8(i + 1)
 		t.Run(fmt.Sprintf("example%d", example), func(t *testing.T) {
-			testBytes(t, 18, func(d uint8) string { return exampleNFilename(d, example) }, true, Day18, want)
+			testSolver(t, 18, func(d uint8) string { return exampleNFilename(d, example) }, true, Day18, want)
 		})
 	}
 }
 
 func TestDay18Part1(t *testing.T) {
 	buf := fileFromFilename(t, filename, 18)
-	got := Day18(buf, true)
+	got, err := Day18(buf, true)
+	if err != nil {
+		t.Fatal(err)
+	}
 	t.Logf("Day 18 Part 1 result: %d", got)
 	// TODO: Correct answer is less than 3962 (answer was too high)
 	// Need to debug why BFS is giving a longer path than optimal
 }
 
 func BenchmarkDay18Part1(b *testing.B) {
-	benchBytes(b, 18, true, Day18)
+	benchSolver(b, 18, true, Day18)
 }
 
 func TestDay18Part2Examples(t *testing.T) {
@@ -46,7 +49,10 @@ func TestDay18Part2Examples(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			got := Day18(maze, false)
+			got, err := Day18(maze, false)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if got != tt.want {
 				t.Fatalf("example %d: want %v but got %v", i+1, tt.want, got)
 			}
@@ -55,9 +61,20 @@ func TestDay18Part2Examples(t *testing.T) {
 }
 
 func TestDay18Part2(t *testing.T) {
-	testBytes(t, 18, filename, false, Day18, 1844)
+	testSolver(t, 18, filename, false, Day18, 1844)
 }
 
 func BenchmarkDay18Part2(b *testing.B) {
-	benchBytes(b, 18, false, Day18)
+	benchSolver(b, 18, false, Day18)
+}
+
+func TestDay18Errors(t *testing.T) {
+	testErrors(t, fromString(Day18), []errorCase[string]{
+		{"empty", "\n", true, "empty maze"},
+		{"character", "#####\n#@.a#\n#.!.#\n#####", true, "line 3: unexpected '!' at column 3"},
+		{"no entrance", "#####\n#.a.#\n#####", true, "want one entrance '@' but got 0"},
+		{"two entrances", "#####\n#@a@#\n#####", true, "want one entrance '@' but got 2"},
+		{"locked", "#######\n#@.A.a#\n#######", true, "cannot collect all keys"},
+		{"border", "####\n@.a#\n####", false, "entrance at 0,1 too close to the border to split"},
+	})
 }
@@ -1,21 +1,23 @@
 package adventofcode2019
 
+import "fmt"
+
 // Day19 solves the "Tractor Beam" puzzle.
 // It tests how many points are affected by a tractor beam.
 func Day19(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
 
 	if part1 {
-		return countBeamPoints(ic, 50), nil
+		return countBeamPoints(ic, 50)
 	}
-	return findSquare(ic, 100), nil
+	return findSquare(ic, 100)
 }
 
 // testPoint checks if a point (x, y) is affected by the tractor beam
-func testPoint(ic *intcode, x, y int) bool {
+func testPoint(ic *Intcode, x, y int) (bool, error) {
 	ic.Reset()
 	inputIdx := 0
 	inputs := [2]int{x, y}
@@ -23,46 +25,71 @@ func testPoint(ic *intcode, x, y int) bool {
 	for {
 		state := ic.Step()
 		switch state {
-		case needsInput:
+		case NeedsInput:
+			if inputIdx == len(inputs) {
+				return false, fmt.Errorf("drone at %d,%d: want %d inputs but program asks for more",
+					x, y, len(inputs))
+			}
 			ic.Input(inputs[inputIdx])
 			inputIdx++
-		case hasOutput:
-			return ic.Output() == 1
-		case halted:
-			return false
+		case HasOutput:
+			return ic.Output() == 1, nil
+		case Halted:
+			return false, nil
+		case Faulted:
+			return false, fmt.Errorf("drone at %d,%d: %w", x, y, ic.Err())
 		}
 	}
 }
 
 // countBeamPoints counts how many points in a size×size grid are affected
-func countBeamPoints(ic *intcode, size int) uint {
+func countBeamPoints(ic *Intcode, size int) (uint, error) {
 	count := uint(0)
 	for y := range size {
 		for x := range size {
-			if testPoint(ic, x, y) {
+			pulled, err := testPoint(ic, x, y)
+			if err != nil {
+				return 0, err
+			}
+			if pulled {
 				count++
 			}
 		}
 	}
-	return count
+	return count, nil
 }
 
-func findSquare(ic *intcode, square int) uint {
+// Limits of the square search: rows below the square without any beam, and
+// rows in total for a beam too narrow to hold the square.
+const (
+	maxEmptyBeamRows = 100
+	maxBeamRows      = 10_000
+)
+
+func findSquare(ic *Intcode, square int) (uint, error) {
 	// y represents the BOTTOM row of the square
 	y := square - 1
 
 	// Track the leftmost x position to avoid searching from 0 each time
 	leftX := 0
+	emptyRows := 0
 
 	for {
 		y++
+		if y > maxBeamRows {
+			return 0, fmt.Errorf("no %dx%d square in the beam up to row %d", square, square, maxBeamRows)
+		}
 
 		// Find the leftmost beam point in this row
 		x := leftX
 		found := false
 
 		for x <= y*2 {
-			if testPoint(ic, x, y) {
+			pulled, err := testPoint(ic, x, y)
+			if err != nil {
+				return 0, err
+			}
+			if pulled {
 				found = true
 				leftX = x
 				break
@@ -71,16 +98,28 @@ func findSquare(ic *intcode, square int) uint {
 		}
 
 		if !found {
+			emptyRows++
+			if emptyRows == maxEmptyBeamRows {
+				return 0, fmt.Errorf("no beam found up to row %d", y)
+			}
 			continue
 		}
+		emptyRows = 0
 
 		// Check if top-right corner of square is in beam
 		topRightX := x + square - 1
 		topRightY := y - square + 1
 
-		if topRightY >= 0 && testPoint(ic, topRightX, topRightY) {
+		if topRightY < 0 {
+			continue
+		}
+		pulled, err := testPoint(ic, topRightX, topRightY)
+		if err != nil {
+			return 0, err
+		}
+		if pulled {
 			// Square fits! Return top-left corner value
-			return uint(x*10000 + topRightY)
+			return uint(x*10000 + topRightY), nil
 		}
 	}
 }
@@ -23,3 +23,13 @@ func BenchmarkDay19Part2(b *testing.B) {
 		_, _ = Day19(buf, false)
 	}
 }
+
+func TestDay19Errors(t *testing.T) {
+	testErrors(t, fromString(Day19), []errorCase[string]{
+		{"fault", "42,0,0,0", true, "drone at 0,0: illegal opcode 42 at address 0"},
+		{"fault part 2", "42,0,0,0", false, "drone at 0,100: illegal opcode 42 at address 0"},
+		{"third input", "3,0,3,0,3,0,99", true, "drone at 0,0: want 2 inputs but program asks for more"},
+		{"no beam", "3,0,3,0,104,0,99", false, "no beam found up to row 199"},
+		{"narrow beam", "3,0,3,1,8,0,1,0,4,0,99", false, "no 100x100 square in the beam up to row 10000"},
+	})
+}
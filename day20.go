@@ -2,7 +2,10 @@ package adventofcode2019
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"image"
+	"slices"
 )
 
 // Day20 solves the "Donut Maze" puzzle.
@@ -10,12 +13,21 @@ import (
 // Part 2 Note: Initial implementation had off-by-one error in isOuterPortal()
 // which caused incorrect answer of 2520. Fixed by changing boundary checks
 // from `>` to `>=`. Correct answer: 7844.
-func Day20(input []byte, part1 bool) uint {
-	maze := parseMaze20(input)
+func Day20(input []byte, part1 bool) (uint, error) {
+	maze, err := parseMaze20(input)
+	if err != nil {
+		return 0, err
+	}
+	var steps uint
 	if part1 {
-		return solveMaze20Part1(maze)
+		steps = solveMaze20Part1(maze)
+	} else {
+		steps = solveMaze20Part2(maze)
+	}
+	if steps == 0 {
+		return 0, errors.New("no path from AA to ZZ")
 	}
-	return solveMaze20Part2(maze)
+	return steps, nil
 }
 
 type day20Puzzle struct {
@@ -26,6 +38,8 @@ type day20Puzzle struct {
 	portalByPos map[image.Point]*portalInfo // position -> portal info (for fast lookup)
 	start       image.Point
 	end         image.Point
+	hasStart    bool        // true if portal AA was found
+	hasEnd      bool        // true if portal ZZ was found
 	innerEdge   image.Point // Track inner boundary for determining inner/outer portals
 	outerEdge   image.Point
 }
@@ -35,7 +49,7 @@ type portalInfo struct {
 	isOuter bool        // true if this is an outer portal
 }
 
-func parseMaze20(input []byte) day20Puzzle {
+func parseMaze20(input []byte) (day20Puzzle, error) {
 	lines := bytes.Split(input, []byte{'\n'})
 	dimY := len(lines)
 	dimX := 0
@@ -57,7 +71,11 @@ func parseMaze20(input []byte) day20Puzzle {
 		maze.grid[y] = make([]byte, dimX)
 		for x := range dimX {
 			if x < len(lines[y]) {
-				maze.grid[y][x] = lines[y][x]
+				c := lines[y][x]
+				if c != ' ' && c != '#' && c != '.' && (c < 'A' || c > 'Z') {
+					return maze, fmt.Errorf("line %d: unexpected %q at column %d", y+1, c, x+1)
+				}
+				maze.grid[y][x] = c
 			} else {
 				maze.grid[y][x] = ' '
 			}
@@ -78,6 +96,24 @@ func parseMaze20(input []byte) day20Puzzle {
 		}
 	}
 
+	if !maze.hasStart {
+		return maze, errors.New("missing entrance AA")
+	}
+	if !maze.hasEnd {
+		return maze, errors.New("missing exit ZZ")
+	}
+	// sorted for a deterministic error
+	labels := make([]string, 0, len(maze.portals))
+	for label := range maze.portals {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		if n := len(maze.portals[label]); n != 2 {
+			return maze, fmt.Errorf("want 2 ends of portal %s but got %d", label, n)
+		}
+	}
+
 	// Build position-to-portal lookup for fast access
 	maze.portalByPos = make(map[image.Point]*portalInfo)
 	for _, positions := range maze.portals {
@@ -93,7 +129,21 @@ func parseMaze20(input []byte) day20Puzzle {
 		}
 	}
 
-	return maze
+	return maze, nil
+}
+
+// addPortal records one end of a portal, AA and ZZ are entrance and exit.
+func (m *day20Puzzle) addPortal(label string, pos image.Point) {
+	switch label {
+	case "AA":
+		m.start = pos
+		m.hasStart = true
+	case "ZZ":
+		m.end = pos
+		m.hasEnd = true
+	default:
+		m.portals[label] = append(m.portals[label], pos)
+	}
 }
 
 func (m *day20Puzzle) findBoundaries() {
@@ -146,13 +196,7 @@ func (m *day20Puzzle) checkPortal(x, y int) {
 			return
 		}
 
-		if label == "AA" {
-			m.start = portalPos
-		} else if label == "ZZ" {
-			m.end = portalPos
-		} else {
-			m.portals[label] = append(m.portals[label], portalPos)
-		}
+		m.addPortal(label, portalPos)
 	}
 
 	// Check vertical portal (this letter + next letter)
@@ -169,13 +213,7 @@ func (m *day20Puzzle) checkPortal(x, y int) {
 			return
 		}
 
-		if label == "AA" {
-			m.start = portalPos
-		} else if label == "ZZ" {
-			m.end = portalPos
-		} else {
-			m.portals[label] = append(m.portals[label], portalPos)
-		}
+		m.addPortal(label, portalPos)
 	}
 }
 
@@ -318,3 +356,12 @@ func solveMaze20Part2(maze day20Puzzle) uint {
 
 	return 0
 }
+
+// day20Frames draws the donut maze with its portal labels.
+func day20Frames(input []byte) ([]*Frame, error) {
+	maze, err := parseMaze20(input)
+	if err != nil {
+		return nil, err
+	}
+	return []*Frame{gridFrame(maze.grid, mazePalette, mazeInk)}, nil
+}
@@ -2,19 +2,20 @@ package adventofcode2019
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
 func TestDay20Part1Example1(t *testing.T) {
-	testBytes(t, 20, example1Filename, true, Day20, 23)
+	testSolver(t, 20, example1Filename, true, Day20, 23)
 }
 
 func TestDay20Part1Example2(t *testing.T) {
-	testBytes(t, 20, example2Filename, true, Day20, 58)
+	testSolver(t, 20, example2Filename, true, Day20, 58)
 }
 
 func TestDay20Part1(t *testing.T) {
-	testBytes(t, 20, filename, true, Day20, 638)
+	testSolver(t, 20, filename, true, Day20, 638)
 }
 
 func TestDay20Part2Example(t *testing.T) {
@@ -22,7 +23,10 @@ func TestDay20Part2Example(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	got := Day20(buf, false)
+	got, err := Day20(buf, false)
+	if err != nil {
+		t.Fatal(err)
+	}
 	const want = 396
 	if got != want {
 		t.Fatalf("Part 2 example: want %v but got %v", want, got)
@@ -30,13 +34,35 @@ func TestDay20Part2Example(t *testing.T) {
 }
 
 func TestDay20Part2(t *testing.T) {
-	testBytes(t, 20, filename, false, Day20, 7844)
+	testSolver(t, 20, filename, false, Day20, 7844)
 }
 
 func BenchmarkDay20Part1(b *testing.B) {
-	benchBytes(b, 20, true, Day20)
+	benchSolver(b, 20, true, Day20)
 }
 
 func BenchmarkDay20Part2(b *testing.B) {
-	benchBytes(b, 20, false, Day20)
+	benchSolver(b, 20, false, Day20)
+}
+
+func TestDay20Errors(t *testing.T) {
+	maze := func(rows ...string) []byte {
+		return []byte(strings.Join(rows, "\n"))
+	}
+	testErrors(t, Day20, []errorCase[[]byte]{
+		{"character", maze("   A", "   A", "  #.#", "  #!#", "  #.#", "   Z", "   Z"), true,
+			"line 4: unexpected '!' at column 4"},
+		{"no entrance", maze("  #.#", "  #.#", "   Z", "   Z"), true, "missing entrance AA"},
+		{"no exit", maze("   A", "   A", "  #.#", "  #.#"), true, "missing exit ZZ"},
+		{"portal", maze("   A", "   A", "  #.#", " BC.#", "  #.#", "   Z", "   Z"), true,
+			"want 2 ends of portal BC but got 1"},
+		{"no path", maze("   A", "   A", "  #.#", "  ###", "  #.#", "   Z", "   Z"), true,
+			"no path from AA to ZZ"},
+	})
+
+	// a valid minimal maze
+	got, err := Day20(maze("   A", "   A", "  #.#", "  #.#", "  #.#", "   Z", "   Z"), true)
+	if err != nil || got != 2 {
+		t.Fatalf("want 2 steps but got %d, %v", got, err)
+	}
 }
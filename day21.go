@@ -1,11 +1,15 @@
 package adventofcode2019
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // Day21 solves the "Springdroid Adventure" puzzle.
 // Part 1 uses WALK mode, Part 2 uses RUN mode.
 func Day21(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
@@ -44,25 +48,18 @@ func Day21(program []byte, part1 bool) (uint, error) {
 		}, "\n") + "\n"
 	}
 
-	return executeSpringdroid(ic, springscript), nil
+	return executeSpringdroid(ic, springscript)
 }
 
-func executeSpringdroid(ic *intcode, springscript string) uint {
-	scriptIdx := 0
-	var lastOutput int
-
-	for {
-		state := ic.Step()
-		switch state {
-		case needsInput:
-			if scriptIdx < len(springscript) {
-				ic.Input(int(springscript[scriptIdx]))
-				scriptIdx++
-			}
-		case hasOutput:
-			lastOutput = ic.Output()
-		case halted:
-			return uint(lastOutput)
-		}
+// executeSpringdroid returns the hull damage that the springdroid reports,
+// ASCII output means it fell into space.
+func executeSpringdroid(ic *Intcode, springscript string) (uint, error) {
+	outputs, _, err := ic.RunUntilInput(asciiInputs(springscript)...)
+	if err != nil {
+		return 0, fmt.Errorf("springdroid: %w", err)
+	}
+	if len(outputs) == 0 || outputs[len(outputs)-1] < 128 {
+		return 0, errors.New("springdroid fell into space")
 	}
+	return uint(outputs[len(outputs)-1]), nil
 }
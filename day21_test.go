@@ -26,3 +26,10 @@ func BenchmarkDay21Part2(b *testing.B) {
 		_, _ = Day21(buf, false)
 	}
 }
+
+func TestDay21Errors(t *testing.T) {
+	testErrors(t, fromString(Day21), []errorCase[string]{
+		{"fault", "42,0,0,0", true, "springdroid: illegal opcode 42 at address 0"},
+		{"fell", "3,0,104,10,99", false, "springdroid fell into space"},
+	})
+}
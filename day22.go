@@ -1,6 +1,7 @@
 package adventofcode2019
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -9,61 +10,127 @@ import (
 // For part 1, it returns the position of card 2019 after shuffling a deck of 10007 cards.
 // For part 2, it returns the card number at position 2020 after shuffling 119315717514047 cards
 // 101741582076661 times.
-func Day22(lines []string, part1 bool) uint {
+func Day22(lines []string, part1 bool) (uint, error) {
+	shuffles, err := parseShuffles(lines)
+	if err != nil {
+		return 0, err
+	}
+
 	if part1 {
 		// For the main puzzle, track card 2019 in a deck of 10007
 		deckSize := uint(10007)
 		cardNumber := uint(2019)
-		position := trackCard(lines, deckSize, cardNumber)
-		return position
+		if err := checkShuffles(shuffles, uint64(deckSize)); err != nil {
+			return 0, err
+		}
+		position := trackCard(shuffles, deckSize, cardNumber)
+		return position, nil
 	}
 
 	// Part 2: Find which card is at position 2020 after many shuffles
 	deckSize := uint64(119315717514047)
-	shuffles := uint64(101741582076661)
+	shuffles64 := uint64(101741582076661)
 	targetPos := uint64(2020)
+	if err := checkShuffles(shuffles, deckSize); err != nil {
+		return 0, err
+	}
 
-	card := findCardAtPosition(lines, deckSize, shuffles, targetPos)
-	return uint(card)
+	card := findCardAtPosition(shuffles, deckSize, shuffles64, targetPos)
+	return uint(card), nil
 }
 
-// trackCard follows a specific card through all shuffle operations
-// and returns its final position in the deck.
-func trackCard(lines []string, deckSize, cardNumber uint) uint {
-	// Card starts at position equal to its number (factory order)
-	pos := cardNumber
+// technique is one of the ways to shuffle the deck.
+type technique int
 
-	for _, line := range lines {
+const (
+	dealIntoNewStack technique = iota
+	cutCards
+	dealWithIncrement
+)
+
+// shuffle is one line of the shuffle process.
+type shuffle struct {
+	technique technique
+	n         int64 // number of cards to cut, or increment
+	line      int   // line number in the input, for errors
+}
+
+// parseShuffles parses the shuffle process, one technique per line.
+func parseShuffles(lines []string) ([]shuffle, error) {
+	shuffles := make([]shuffle, 0, len(lines))
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 
-		if line == "deal into new stack" {
-			// Reverse: position becomes (deckSize - 1 - position)
-			pos = deckSize - 1 - pos
-		} else if strings.HasPrefix(line, "cut ") {
-			// Cut N cards
-			nStr := strings.TrimPrefix(line, "cut ")
-			n, err := strconv.Atoi(nStr)
+		s := shuffle{line: i + 1}
+		var arg string
+		switch {
+		case line == "deal into new stack":
+			s.technique = dealIntoNewStack
+		case strings.HasPrefix(line, "cut "):
+			s.technique = cutCards
+			arg = strings.TrimPrefix(line, "cut ")
+		case strings.HasPrefix(line, "deal with increment "):
+			s.technique = dealWithIncrement
+			arg = strings.TrimPrefix(line, "deal with increment ")
+		default:
+			return nil, fmt.Errorf("line %d: unknown technique %q", i+1, line)
+		}
+		if s.technique != dealIntoNewStack {
+			n, err := strconv.ParseInt(arg, 10, 64)
 			if err != nil {
-				continue
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
 			}
-			// Position shifts by -N (with wrapping)
-			if n >= 0 {
-				pos = (pos - uint(n) + deckSize) % deckSize
-			} else {
-				pos = (pos + uint(-n)) % deckSize
-			}
-		} else if strings.HasPrefix(line, "deal with increment ") {
-			// Deal with increment N
-			nStr := strings.TrimPrefix(line, "deal with increment ")
-			n, err := strconv.Atoi(nStr)
-			if err != nil {
-				continue
+			if s.technique == dealWithIncrement && n <= 0 {
+				return nil, fmt.Errorf("line %d: want positive increment but got %d", i+1, n)
 			}
+			s.n = n
+		}
+		shuffles = append(shuffles, s)
+	}
+	return shuffles, nil
+}
+
+// checkShuffles returns an error if a cut takes more cards than a deck of
+// the given size holds, or if dealing with an increment would put two cards
+// on the same position.
+func checkShuffles(shuffles []shuffle, deckSize uint64) error {
+	for _, s := range shuffles {
+		if s.technique == cutCards && (s.n >= int64(deckSize) || s.n <= -int64(deckSize)) {
+			return fmt.Errorf("line %d: want fewer than %d cards to cut but got %d",
+				s.line, deckSize, s.n)
+		}
+		if s.technique != dealWithIncrement {
+			continue
+		}
+		if gcd(int(s.n), int(deckSize%uint64(s.n))) != 1 {
+			return fmt.Errorf("line %d: increment %d shares a factor with deck size %d",
+				s.line, s.n, deckSize)
+		}
+	}
+	return nil
+}
+
+// trackCard follows a specific card through all shuffle operations
+// and returns its final position in the deck.
+func trackCard(shuffles []shuffle, deckSize, cardNumber uint) uint {
+	// Card starts at position equal to its number (factory order)
+	pos := cardNumber
+	size := int64(deckSize)
+
+	for _, s := range shuffles {
+		switch s.technique {
+		case dealIntoNewStack:
+			// Reverse: position becomes (deckSize - 1 - position)
+			pos = deckSize - 1 - pos
+		case cutCards:
+			// Position shifts by -N (with wrapping)
+			pos = uint(modAdd(int64(pos), -s.n%size, size))
+		case dealWithIncrement:
 			// Position multiplies by N (mod deckSize)
-			pos = (pos * uint(n)) % deckSize
+			pos = uint(modMul(int64(pos), s.n, size))
 		}
 	}
 
@@ -72,55 +139,28 @@ func trackCard(lines []string, deckSize, cardNumber uint) uint {
 
 // shuffleDeck performs all shuffle operations and returns the final deck state.
 // This is used for testing with small decks.
-func shuffleDeck(lines []string, deckSize uint) []uint {
+func shuffleDeck(shuffles []shuffle, deckSize uint) []uint {
 	deck := make([]uint, deckSize)
 	for i := range deckSize {
 		deck[i] = i
 	}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		if line == "deal into new stack" {
+	for _, s := range shuffles {
+		switch s.technique {
+		case dealIntoNewStack:
 			// Reverse the deck
 			for i, j := uint(0), deckSize-1; i < j; i, j = i+1, j-1 {
 				deck[i], deck[j] = deck[j], deck[i]
 			}
-		} else if strings.HasPrefix(line, "cut ") {
-			// Cut N cards
-			nStr := strings.TrimPrefix(line, "cut ")
-			n, err := strconv.Atoi(nStr)
-			if err != nil {
-				continue
-			}
-
-			if n >= 0 {
-				// Cut from top
-				cut := uint(n) % deckSize
-				newDeck := make([]uint, deckSize)
-				copy(newDeck, deck[cut:])
-				copy(newDeck[deckSize-cut:], deck[:cut])
-				deck = newDeck
-			} else {
-				// Cut from bottom
-				cut := uint(-n) % deckSize
-				newDeck := make([]uint, deckSize)
-				copy(newDeck, deck[deckSize-cut:])
-				copy(newDeck[cut:], deck[:deckSize-cut])
-				deck = newDeck
-			}
-		} else if strings.HasPrefix(line, "deal with increment ") {
-			// Deal with increment N
-			nStr := strings.TrimPrefix(line, "deal with increment ")
-			n, err := strconv.Atoi(nStr)
-			if err != nil {
-				continue
-			}
-			increment := uint(n)
-
+		case cutCards:
+			// Cut N cards from the top, negative N from the bottom
+			cut := uint(modAdd(s.n%int64(deckSize), 0, int64(deckSize)))
+			newDeck := make([]uint, deckSize)
+			copy(newDeck, deck[cut:])
+			copy(newDeck[deckSize-cut:], deck[:cut])
+			deck = newDeck
+		case dealWithIncrement:
+			increment := uint(s.n)
 			newDeck := make([]uint, deckSize)
 			pos := uint(0)
 			for i := range deckSize {
@@ -136,7 +176,7 @@ func shuffleDeck(lines []string, deckSize uint) []uint {
 
 // findCardAtPosition finds which card ends up at a given position after applying
 // the shuffle operations a specified number of times.
-func findCardAtPosition(lines []string, deckSize, times, position uint64) uint64 {
+func findCardAtPosition(shuffles []shuffle, deckSize, times, position uint64) uint64 {
 	// Convert to signed for modular arithmetic (needed for negative intermediate values)
 	m := int64(deckSize)
 	n := int64(times)
@@ -146,37 +186,24 @@ func findCardAtPosition(lines []string, deckSize, times, position uint64) uint64
 	a, b := int64(1), int64(0)
 
 	// Process operations in reverse order to build inverse transformation
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		if line == "deal into new stack" {
+	for i := len(shuffles) - 1; i >= 0; i-- {
+		s := shuffles[i]
+		switch s.technique {
+		case dealIntoNewStack:
 			// Inverse of reverse: pos -> deckSize - 1 - pos
 			// As linear: new_pos = -1 * pos + (deckSize - 1)
 			// Inverse is same: a' = -1, b' = deckSize - 1
 			a = modMul(-a, 1, m)
 			b = modAdd(modMul(-b, 1, m), m-1, m)
-		} else if strings.HasPrefix(line, "cut ") {
-			nStr := strings.TrimPrefix(line, "cut ")
-			cutN, err := strconv.ParseInt(nStr, 10, 64)
-			if err != nil {
-				continue
-			}
+		case cutCards:
 			// Forward: pos -> pos - n
 			// Inverse: pos -> pos + n
 			// a' = a, b' = b + n
-			b = modAdd(b, cutN, m)
-		} else if strings.HasPrefix(line, "deal with increment ") {
-			nStr := strings.TrimPrefix(line, "deal with increment ")
-			inc, err := strconv.ParseInt(nStr, 10, 64)
-			if err != nil {
-				continue
-			}
+			b = modAdd(b, s.n, m)
+		case dealWithIncrement:
 			// Forward: pos -> pos * n
 			// Inverse: pos -> pos * n^(-1)
-			incInv := modInverse(inc, m)
+			incInv := modInverse(s.n, m)
 			a = modMul(a, incInv, m)
 			b = modMul(b, incInv, m)
 		}
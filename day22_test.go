@@ -6,6 +6,15 @@ import (
 	"testing"
 )
 
+func testShuffles(t *testing.T, filenameFunc func(uint8) string) []shuffle {
+	t.Helper()
+	shuffles, err := parseShuffles(testLinesFromFilename(t, filenameFunc(22)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shuffles
+}
+
 func TestDay22Part1Examples(t *testing.T) {
 	tests := []struct {
 		filenameFunc func(uint8) string
@@ -31,8 +40,7 @@ func TestDay22Part1Examples(t *testing.T) {
 
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("example%d", i+1), func(t *testing.T) {
-			lines := testLinesFromFilename(t, tt.filenameFunc(22))
-			deck := shuffleDeck(lines, 10)
+			deck := shuffleDeck(testShuffles(t, tt.filenameFunc), 10)
 			if !slices.Equal(deck, tt.want) {
 				t.Fatalf("want %v but got %v", tt.want, deck)
 			}
@@ -77,11 +85,11 @@ func TestDay22Part2InverseLogic(t *testing.T) {
 
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("example%d", i+1), func(t *testing.T) {
-			lines := testLinesFromFilename(t, tt.filenameFunc(22))
+			shuffles := testShuffles(t, tt.filenameFunc)
 
 			// Use findCardAtPosition to verify we get the same result
 			for pos := range deckSize {
-				card := findCardAtPosition(lines, deckSize, 1, uint64(pos))
+				card := findCardAtPosition(shuffles, deckSize, 1, uint64(pos))
 				if uint(card) != tt.want[pos] {
 					t.Errorf("at position %d: want card %d but got %d", pos, tt.want[pos], card)
 				}
@@ -95,7 +103,7 @@ func TestDay22Part2InverseLogic(t *testing.T) {
 func TestDay22Part2MultipleShuffles(t *testing.T) {
 	const deckSize = 10
 
-	lines := testLinesFromFilename(t, example1Filename(22))
+	shuffles := testShuffles(t, example1Filename)
 
 	// Test with different numbers of shuffles
 	for _, times := range []uint64{1, 2, 3, 5, 10} {
@@ -106,7 +114,7 @@ func TestDay22Part2MultipleShuffles(t *testing.T) {
 				pos := uint(cardNum)
 				// Apply shuffle 'times' times
 				for range times {
-					pos = trackCard(lines, deckSize, pos)
+					pos = trackCard(shuffles, deckSize, pos)
 				}
 				// deck[pos] tells us which card is at position pos
 				deck[pos] = uint(cardNum)
@@ -114,7 +122,7 @@ func TestDay22Part2MultipleShuffles(t *testing.T) {
 
 			// Now verify our findCardAtPosition gives the same result
 			for pos := range deckSize {
-				card := findCardAtPosition(lines, deckSize, times, uint64(pos))
+				card := findCardAtPosition(shuffles, deckSize, times, uint64(pos))
 				if uint(card) != deck[pos] {
 					t.Errorf("shuffle %d, pos %d: want card %d but got %d", times, pos, deck[pos], card)
 				}
@@ -130,3 +138,19 @@ func TestDay22Part2(t *testing.T) {
 func BenchmarkDay22Part2(b *testing.B) {
 	benchLines(b, 22, false, Day22)
 }
+
+func TestDay22Errors(t *testing.T) {
+	testErrors(t, Day22, []errorCase[[]string]{
+		{"technique", []string{"cut 3", "deal backwards"}, true, `line 2: unknown technique "deal backwards"`},
+		{"number", []string{"cut x"}, true, `line 1: strconv.ParseInt: parsing "x": invalid syntax`},
+		{"increment", []string{"deal into new stack", "deal with increment 0"}, true,
+			"line 2: want positive increment but got 0"},
+		{"cut", []string{"cut -10007"}, true, "line 1: want fewer than 10007 cards to cut but got -10007"},
+		{"cut part 2", []string{"deal into new stack", "cut 9223372036854775807"}, false,
+			"line 2: want fewer than 119315717514047 cards to cut but got 9223372036854775807"},
+		{"cut min", []string{"cut -9223372036854775808"}, false,
+			"line 1: want fewer than 119315717514047 cards to cut but got -9223372036854775808"},
+		{"factor", []string{"deal with increment 10007"}, true,
+			"line 1: increment 10007 shares a factor with deck size 10007"},
+	})
+}
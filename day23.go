@@ -1,10 +1,12 @@
 package adventofcode2019
 
+import "fmt"
+
 // Day23 simulates a network of 50 Intcode computers.
 // For part 1, it returns the Y value of the first packet sent to address 255.
 // For part 2, it returns the first Y value delivered by the NAT twice in a row.
 func Day23(program []byte, part1 bool) (uint, error) {
-	ic, err := newIntcode(program)
+	ic, err := NewIntcode(program)
 	if err != nil {
 		return 0, err
 	}
@@ -13,7 +15,7 @@ func Day23(program []byte, part1 bool) (uint, error) {
 	const natAddress = 255
 
 	// Create 50 computers
-	computers := make([]*intcode, networkSize)
+	computers := make([]*Intcode, networkSize)
 	for i := range networkSize {
 		computers[i] = ic.Clone()
 	}
@@ -24,94 +26,88 @@ func Day23(program []byte, part1 bool) (uint, error) {
 	// Output buffers for each computer (collecting dest, X, Y)
 	outBuffers := make([][]int, networkSize)
 
-	// Track if each computer has received its address
-	needsAddress := make([]bool, networkSize)
-	for i := range networkSize {
-		needsAddress[i] = true
-	}
-
-	// NAT state for part 2
+	// first packet sent to the NAT for part 1
+	var firstNATY int
+	// NAT state for part 2, the last packet wins
 	var natX, natY int
 	hasNAT := false
 	var lastNATY int
 	hasLastNATY := false
 	idleCycles := 0
 
-	// Run each computer to next I/O point
-	runToIO := func(addr int) state {
-		for {
-			state := computers[addr].Step()
-			if state != running {
-				return state
+	// Run a computer until it waits for input, route its packets
+	noPacket := []int{-1}
+	send := func(addr int, inputs []int) (activity bool, natSeen bool, err error) {
+		outputs, state, err := computers[addr].RunUntilInput(inputs...)
+		if err != nil {
+			return false, false, fmt.Errorf("computer %d: %w", addr, err)
+		}
+		if state == Halted {
+			// nobody would read its packets
+			return false, false, fmt.Errorf("computer %d halted", addr)
+		}
+		outBuffers[addr] = append(outBuffers[addr], outputs...)
+		for len(outBuffers[addr]) >= 3 {
+			dest := outBuffers[addr][0]
+			x := outBuffers[addr][1]
+			y := outBuffers[addr][2]
+			outBuffers[addr] = outBuffers[addr][3:]
+
+			if dest == natAddress {
+				if !hasNAT {
+					firstNATY = y
+				}
+				natX, natY = x, y
+				hasNAT = true
+				natSeen = true
+			} else if dest >= 0 && dest < networkSize {
+				queues[dest] = append(queues[dest], x, y)
 			}
 		}
+		return len(outputs) > 0, natSeen, nil
+	}
+
+	// Boot each computer with its network address
+	for addr := range networkSize {
+		_, natSeen, err := send(addr, []int{addr})
+		if err != nil {
+			return 0, err
+		}
+		if natSeen && part1 {
+			return uint(firstNATY), nil
+		}
 	}
 
 	for {
 		activity := false
 
 		for addr := range networkSize {
-			state := runToIO(addr)
-
-			switch state {
-			case needsInput:
-				if needsAddress[addr] {
-					computers[addr].Input(addr)
-					needsAddress[addr] = false
-					activity = true
-				} else if len(queues[addr]) > 0 {
-					computers[addr].Input(queues[addr][0])
-					queues[addr] = queues[addr][1:]
-					activity = true
-				} else {
-					computers[addr].Input(-1)
-				}
-			case hasOutput:
+			inputs := noPacket
+			if len(queues[addr]) > 0 {
+				inputs = queues[addr]
+				queues[addr] = nil
 				activity = true
-				outBuffers[addr] = append(outBuffers[addr], computers[addr].Output())
-				if len(outBuffers[addr]) == 3 {
-					dest := outBuffers[addr][0]
-					x := outBuffers[addr][1]
-					y := outBuffers[addr][2]
-					outBuffers[addr] = outBuffers[addr][:0]
-
-					if dest == natAddress {
-						if part1 {
-							return uint(y), nil
-						}
-						natX, natY = x, y
-						hasNAT = true
-					} else if dest >= 0 && dest < networkSize {
-						queues[dest] = append(queues[dest], x, y)
-					}
-				}
-			case halted:
-				// Computer halted
 			}
+			sent, natSeen, err := send(addr, inputs)
+			if err != nil {
+				return 0, err
+			}
+			if natSeen && part1 {
+				return uint(firstNATY), nil
+			}
+			activity = activity || sent
 		}
 
 		// Part 2: Check for network idle
 		if !part1 && hasNAT && !activity {
-			queuesEmpty := true
-			for i := range networkSize {
-				if len(queues[i]) > 0 {
-					queuesEmpty = false
-					break
-				}
-			}
-
-			if queuesEmpty {
-				idleCycles++
-				if idleCycles > 2 {
-					if hasLastNATY && lastNATY == natY {
-						return uint(natY), nil
-					}
-					lastNATY = natY
-					hasLastNATY = true
-					queues[0] = append(queues[0], natX, natY)
-					idleCycles = 0
+			idleCycles++
+			if idleCycles > 2 {
+				if hasLastNATY && lastNATY == natY {
+					return uint(natY), nil
 				}
-			} else {
+				lastNATY = natY
+				hasLastNATY = true
+				queues[0] = append(queues[0], natX, natY)
 				idleCycles = 0
 			}
 		} else {
@@ -23,3 +23,23 @@ func BenchmarkDay23Part2(b *testing.B) {
 		_, _ = Day23(buf, false)
 	}
 }
+
+func TestDay23Errors(t *testing.T) {
+	testErrors(t, fromString(Day23), []errorCase[string]{
+		{"fault", "42,0,0,0", true, "computer 0: illegal opcode 42 at address 0"},
+		{"halted", "99", false, "computer 0 halted"},
+	})
+}
+
+// TestDay23FirstNATPacket sends two packets to the NAT in one batch of
+// outputs, part 1 wants the first one.
+func TestDay23FirstNATPacket(t *testing.T) {
+	program := "3,100,104,255,104,1,104,10,104,255,104,2,104,20,3,100,1105,1,14"
+	got, err := Day23([]byte(program), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint(10); want != got {
+		t.Fatalf("want %d but got %d", want, got)
+	}
+}
@@ -1,31 +1,52 @@
 package adventofcode2019
 
-import "math/bits"
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
 
 // Day24 simulates bug evolution on a 5x5 grid.
 // Part 1: biodiversity rating of first repeated layout.
 // Part 2: bug count after 200 minutes in recursive grids.
-func Day24(lines []string, part1 bool) uint {
-	grid := parseGrid24(lines)
+func Day24(lines []string, part1 bool) (uint, error) {
+	grid, err := parseGrid24(lines)
+	if err != nil {
+		return 0, err
+	}
 	if part1 {
-		return day24Part1(grid)
+		return day24Part1(grid), nil
+	}
+	if grid&(1<<12) != 0 {
+		return 0, errors.New("center tile holds a nested grid and must not contain a bug")
 	}
-	return day24Part2(grid, 200)
+	return day24Part2(grid, 200), nil
 }
 
-func parseGrid24(lines []string) uint32 {
+// parseGrid24 reads 5 lines of 5 tiles, '#' is a bug, '.' and '?' are empty.
+// A trailing carriage return is ignored.
+func parseGrid24(lines []string) (uint32, error) {
+	if len(lines) != 5 {
+		return 0, fmt.Errorf("want 5 lines but got %d", len(lines))
+	}
 	var grid uint32
-	for y := range 5 {
-		if y >= len(lines) {
-			break
+	for y, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) != 5 {
+			return 0, fmt.Errorf("line %d: want 5 tiles but got %d", y+1, len(line))
 		}
 		for x := range 5 {
-			if x < len(lines[y]) && lines[y][x] == '#' {
+			switch line[x] {
+			case '#':
 				grid |= 1 << (y*5 + x)
+			case '.', '?':
+			default:
+				return 0, fmt.Errorf("line %d: unexpected %q at column %d", y+1, line[x], x+1)
 			}
 		}
 	}
-	return grid
+	return grid, nil
 }
 
 func day24Part1(grid uint32) uint {
@@ -192,3 +213,31 @@ func day24Part2(initial uint32, minutes int) uint {
 	}
 	return total
 }
+
+// day24Frames draws the bugs of each minute until a layout appears twice,
+// the last frame is the repeated layout.
+func day24Frames(input []byte) ([]*Frame, error) {
+	grid, err := parseGrid24(strings.Split(strings.TrimSpace(string(input)), "\n"))
+	if err != nil {
+		return nil, err
+	}
+	var frames []*Frame
+	draw := func(grid uint32) {
+		f := NewFrame(5, 5, []Ink{{'.', black}, {'#', green}})
+		for i := range 25 {
+			if grid&(1<<i) != 0 {
+				f.Set(i%5, i/5, 1)
+			}
+		}
+		frames = append(frames, f)
+	}
+	seen := make(map[uint32]bool)
+	for !seen[grid] {
+		seen[grid] = true
+		draw(grid)
+		grid = evolve(grid)
+	}
+	// the first layout that appears twice
+	draw(grid)
+	return frames, nil
+}
@@ -1,25 +1,48 @@
 package adventofcode2019
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 )
 
 func TestDay24Part1Example(t *testing.T) {
 	lines := testLinesFromFilename(t, exampleFilename(24))
-	got := Day24(lines, true)
+	got, err := Day24(lines, true)
+	if err != nil {
+		t.Fatal(err)
+	}
 	want := uint(2129920)
 	if got != want {
 		t.Fatalf("want %d but got %d", want, got)
 	}
 }
 
+// TestDay24CRLF reads the example with Windows line endings.
+func TestDay24CRLF(t *testing.T) {
+	input := bytes.ReplaceAll(fileFromFilename(t, exampleFilename, 24), []byte("\n"), []byte("\r\n"))
+	got, err := Day24(strings.Split(strings.TrimSpace(string(input)), "\n"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint(2129920); want != got {
+		t.Fatalf("want %d but got %d", want, got)
+	}
+	if _, err := Visualize(24, input); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestDay24Part1(t *testing.T) {
 	testLines(t, 24, filename, true, Day24, 20751345)
 }
 
 func TestDay24Part2Example(t *testing.T) {
 	lines := testLinesFromFilename(t, exampleFilename(24))
-	grid := parseGrid24(lines)
+	grid, err := parseGrid24(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
 	got := day24Part2(grid, 10)
 	want := uint(99)
 	if got != want {
@@ -38,3 +61,13 @@ func BenchmarkDay24Part1(b *testing.B) {
 func BenchmarkDay24Part2(b *testing.B) {
 	benchLines(b, 24, false, Day24)
 }
+
+func TestDay24Errors(t *testing.T) {
+	testErrors(t, Day24, []errorCase[[]string]{
+		{"lines", []string{"....#", "#..#."}, true, "want 5 lines but got 2"},
+		{"tiles", []string{"....#", "#..#.", "#..##", "..#.", "#...."}, true, "line 4: want 5 tiles but got 4"},
+		{"tile", []string{"....#", "#..#.", "#.x##", "..#..", "#...."}, true, "line 3: unexpected 'x' at column 3"},
+		{"center", []string{"....#", "#..#.", "#.###", "..#..", "#...."}, false,
+			"center tile holds a nested grid and must not contain a bug"},
+	})
+}
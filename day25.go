@@ -1,18 +1,24 @@
 package adventofcode2019
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 )
 
 // Day25 solves the Cryostasis text adventure.
-func Day25(lines []string, part1 bool) uint {
+func Day25(lines []string, part1 bool) (uint, error) {
 	if !part1 {
-		return 0
+		return 0, nil
+	}
+	if len(lines) == 0 {
+		return 0, errors.New("empty program")
+	}
+	prog, err := split(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return 0, err
 	}
-
-	prog := mustSplit(strings.TrimSpace(lines[0]))
 
 	// Build room graph using checkpoint-based exploration
 	graph := buildRoomGraphFast(prog)
@@ -45,15 +51,21 @@ func Day25(lines []string, part1 bool) uint {
 		}
 	}
 
-	if len(items) == 0 || securityRoom == "" {
-		return 0
+	if securityRoom == "" {
+		return 0, errors.New("no security checkpoint found")
+	}
+	if len(items) == 0 {
+		return 0, errors.New("no items found")
 	}
 
 	// Build path to collect all items and reach security
 	path := buildCollectionPath(graph, items, securityRoom)
 
 	// Try all item combinations with all possible security directions
-	return tryItemCombos(prog, items, path, securityDirs)
+	if pw := tryItemCombos(prog, items, path, securityDirs); pw != 0 {
+		return pw, nil
+	}
+	return 0, errors.New("no item combination passes the security checkpoint")
 }
 
 // vmSnapshot represents a saved VM state
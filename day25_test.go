@@ -11,3 +11,10 @@ func TestDay25Part1(t *testing.T) {
 func BenchmarkDay25Part1(b *testing.B) {
 	benchLines(b, 25, true, Day25)
 }
+
+func TestDay25Errors(t *testing.T) {
+	testErrors(t, Day25, []errorCase[[]string]{
+		{"empty", nil, true, "empty program"},
+		{"opcode", []string{"104,x,99"}, true, `opcode 1: want number but got "x"`},
+	})
+}
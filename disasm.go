@@ -0,0 +1,96 @@
+package adventofcode2019
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtins describes the built-in Intcode instruction set for decoding.
+var builtins = map[int]Opcode{
+	1:  {Code: 1, Name: "add", Arity: 3, Writes: []int{3}},
+	2:  {Code: 2, Name: "mul", Arity: 3, Writes: []int{3}},
+	3:  {Code: 3, Name: "in", Arity: 1, Writes: []int{1}},
+	4:  {Code: 4, Name: "out", Arity: 1},
+	5:  {Code: 5, Name: "jnz", Arity: 2},
+	6:  {Code: 6, Name: "jz", Arity: 2},
+	7:  {Code: 7, Name: "lt", Arity: 3, Writes: []int{3}},
+	8:  {Code: 8, Name: "eq", Arity: 3, Writes: []int{3}},
+	9:  {Code: 9, Name: "arb", Arity: 1},
+	99: {Code: 99, Name: "hlt"},
+}
+
+// Instruction is a decoded Intcode instruction.
+type Instruction struct {
+	Addr   int    // address of the instruction
+	Opcode int    // opcode without modes
+	Name   string // mnemonic
+	Modes  []int  // parameter modes: 0 position, 1 immediate, 2 relative
+	Params []int  // raw parameter values
+}
+
+// Len returns the number of memory cells occupied by the instruction.
+func (a Instruction) Len() int {
+	return 1 + len(a.Params)
+}
+
+// String returns the instruction in assembler notation, i.e. [n] for
+// position mode, n for immediate mode and [rb+n] for relative mode.
+func (a Instruction) String() string {
+	var sb strings.Builder
+	sb.WriteString(a.Name)
+	for i, p := range a.Params {
+		if i == 0 {
+			sb.WriteByte(' ')
+		} else {
+			sb.WriteString(", ")
+		}
+		switch a.Modes[i] {
+		case 0:
+			fmt.Fprintf(&sb, "[%d]", p)
+		case 1:
+			fmt.Fprintf(&sb, "%d", p)
+		case 2:
+			fmt.Fprintf(&sb, "[rb%+d]", p)
+		default:
+			fmt.Fprintf(&sb, "?%d:%d", a.Modes[i], p)
+		}
+	}
+	return sb.String()
+}
+
+// Decode decodes the instruction at addr. It returns false if addr does not
+// hold a known built-in or registered opcode.
+func (ic *Intcode) Decode(addr int) (Instruction, bool) {
+	if addr < 0 || addr >= len(ic.mem) {
+		return Instruction{}, false
+	}
+	raw := ic.mem[addr]
+	if raw < 0 {
+		return Instruction{}, false
+	}
+	code := raw % 100
+	op, ok := builtins[code]
+	if !ok {
+		op, ok = ic.ext[code]
+	}
+	if !ok {
+		return Instruction{}, false
+	}
+	in := Instruction{
+		Addr:   addr,
+		Opcode: code,
+		Name:   op.Name,
+		Modes:  make([]int, op.Arity),
+		Params: make([]int, op.Arity),
+	}
+	for n := 1; n <= op.Arity; n++ {
+		in.Modes[n-1] = (raw / pow10(n+1)) % 10
+		in.Params[n-1] = ic.Mem(addr + n)
+	}
+	return in, true
+}
+
+// IP returns the current instruction pointer.
+func (ic *Intcode) IP() int {
+	return ic.ip
+}
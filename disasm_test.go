@@ -0,0 +1,30 @@
+package adventofcode2019
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	ic, err := NewIntcode([]byte("1002,4,3,4,33,21101,-1,7,3,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr int
+		want string
+	}{
+		{0, "mul [4], 3, [4]"},
+		{5, "add -1, 7, [rb+3]"},
+		{9, "hlt"},
+	}
+	for _, tt := range tests {
+		in, ok := ic.Decode(tt.addr)
+		if !ok {
+			t.Fatalf("address %d: cannot decode", tt.addr)
+		}
+		if got := in.String(); tt.want != got {
+			t.Fatalf("address %d: want %q but got %q", tt.addr, tt.want, got)
+		}
+	}
+	if _, ok := ic.Decode(4); ok {
+		t.Fatal("want illegal opcode 33 to fail decoding")
+	}
+}
@@ -0,0 +1,392 @@
+package adventofcode2019
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Param is a memory address of an Intcode program that GoalSeek may patch,
+// and the inclusive range of values it may take.
+type Param struct {
+	Addr     int
+	Min, Max int
+}
+
+// span returns the number of values of a minus one.
+func (a Param) span() int {
+	return a.Max - a.Min
+}
+
+// seekMethod is how goalSeek found its solution.
+type seekMethod int
+
+const (
+	seekLinear     seekMethod = iota // solved the probed linear equation
+	seekMonotone                     // binary search over one parameter
+	seekBruteForce                   // tried all combinations
+)
+
+// GoalSeek returns values for params, in order, such that running the
+// program leaves want at memory address addr, e.g. noun and verb for Day 2.
+// The program must halt without input; combinations that fault, or that
+// run for more than maxSeekSteps instructions, are skipped. Patches start
+// from the program as parsed, changes to the memory of ic are ignored, and
+// the runs are not recorded in the coverage of ic.
+//
+// GoalSeek probes the result as a function of each parameter. If it looks
+// linear, the equation is solved directly; if it looks monotone, the largest
+// range is binary searched. Otherwise, and whenever a shortcut finds nothing,
+// all combinations are tried in parallel, so the shortcuts never lose a
+// solution. If several combinations match, any of them may be returned, but
+// always the same one for the same arguments.
+func GoalSeek(ic *Intcode, params []Param, addr, want int) ([]int, error) {
+	values, _, err := goalSeek(ic, params, addr, want, runtime.GOMAXPROCS(0))
+	return values, err
+}
+
+func goalSeek(ic *Intcode, params []Param, addr, want, workers int) ([]int, seekMethod, error) {
+	if len(params) == 0 {
+		return nil, 0, errors.New("no parameters")
+	}
+	for i, p := range params {
+		if p.Addr < 0 || p.Addr >= MaxMem {
+			return nil, 0, fmt.Errorf("parameter %d: address %d out of memory", i, p.Addr)
+		}
+		if p.Min > p.Max {
+			return nil, 0, fmt.Errorf("parameter %d: want min <= max but got %d > %d", i, p.Min, p.Max)
+		}
+	}
+	probe := ic.Clone()
+	// coverage is not safe for the parallel workers
+	probe.Cover(nil)
+	s := seeker{ic: probe, params: params, addr: addr, want: want}
+	if values, ok := s.linear(); ok {
+		return values, seekLinear, nil
+	}
+	if values, ok := s.monotone(workers); ok {
+		return values, seekMonotone, nil
+	}
+	if values, ok := s.bruteForce(workers); ok {
+		return values, seekBruteForce, nil
+	}
+	return nil, 0, fmt.Errorf("no parameters leave %d at address %d", want, addr)
+}
+
+// seeker holds the goal of a search.
+type seeker struct {
+	ic     *Intcode // used by probes, workers use clones
+	params []Param
+	addr   int
+	want   int
+}
+
+// maxSeekSteps limits each run of the program, patched values may turn it
+// into an endless loop.
+const maxSeekSteps = 1_000_000
+
+// eval runs ic with values patched in and returns the value at the target
+// address, false if the program faults, needs input or runs too long.
+func (s *seeker) eval(ic *Intcode, values []int) (int, bool) {
+	ic.Reset()
+	for i, p := range s.params {
+		ic.SetMem(p.Addr, values[i])
+	}
+	for range maxSeekSteps {
+		switch ic.Step() {
+		case Halted:
+			return ic.Mem(s.addr), true
+		case NeedsInput, Faulted:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// mins returns the smallest value of each parameter.
+func (s *seeker) mins() []int {
+	values := make([]int, len(s.params))
+	for i, p := range s.params {
+		values[i] = p.Min
+	}
+	return values
+}
+
+// linear probes f(x) = f(min) + Σ a[i]·(x[i]-min[i]) by measuring each slope
+// a[i] at the minimum and checking the prediction at each maximum, at all
+// maxima and at the middle. If the probes agree, the equation is solved and
+// the solution checked by running the program.
+func (s *seeker) linear() ([]int, bool) {
+	base := s.mins()
+	f0, ok := s.eval(s.ic, base)
+	if !ok {
+		return nil, false
+	}
+	a := make([]int, len(s.params))
+	at := func(i, v int) []int {
+		values := slices.Clone(base)
+		values[i] = v
+		return values
+	}
+	for i, p := range s.params {
+		if p.span() == 0 {
+			continue
+		}
+		f, ok := s.eval(s.ic, at(i, p.Min+1))
+		if !ok {
+			return nil, false
+		}
+		a[i] = f - f0
+	}
+
+	predict := func(values []int) int {
+		f := f0
+		for i, p := range s.params {
+			f += a[i] * (values[i] - p.Min)
+		}
+		return f
+	}
+	probes := make([][]int, 0, len(s.params)+2)
+	for i, p := range s.params {
+		probes = append(probes, at(i, p.Max))
+	}
+	maxs, mids := make([]int, len(s.params)), make([]int, len(s.params))
+	for i, p := range s.params {
+		maxs[i], mids[i] = p.Max, p.Min+p.span()/2
+	}
+	probes = append(probes, maxs, mids)
+	for _, values := range probes {
+		if f, ok := s.eval(s.ic, values); !ok || f != predict(values) {
+			return nil, false
+		}
+	}
+
+	spans := make([]int, len(s.params))
+	for i, p := range s.params {
+		spans[i] = p.span()
+	}
+	d := make([]int, len(s.params))
+	if !solveLinear(a, spans, s.want-f0, d) {
+		return nil, false
+	}
+	for i, p := range s.params {
+		d[i] += p.Min
+	}
+	if f, ok := s.eval(s.ic, d); !ok || f != s.want {
+		return nil, false
+	}
+	return d, true
+}
+
+// solveLinear finds d with 0 <= d[i] <= spans[i] and Σ a[i]·d[i] = rhs.
+// Parameters with larger coefficients are fixed first; the range of what
+// the remaining ones can contribute narrows each choice to an interval,
+// which is a single value for nested coefficients such as 100·noun + verb.
+// Within an interval, smaller values are tried first.
+func solveLinear(a, spans []int, rhs int, d []int) bool {
+	order := make([]int, len(a))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return abs(a[j]) - abs(a[i])
+	})
+	// lo[k], hi[k] bound what params order[k:] can contribute
+	lo, hi := make([]int, len(a)+1), make([]int, len(a)+1)
+	for k := len(order) - 1; k >= 0; k-- {
+		c := a[order[k]] * spans[order[k]]
+		lo[k], hi[k] = lo[k+1]+min(c, 0), hi[k+1]+max(c, 0)
+	}
+
+	var solve func(k, rhs int) bool
+	solve = func(k, rhs int) bool {
+		if k == len(order) {
+			return rhs == 0
+		}
+		if rhs < lo[k] || rhs > hi[k] {
+			return false
+		}
+		i := order[k]
+		if a[i] == 0 {
+			d[i] = 0
+			return solve(k+1, rhs)
+		}
+		// a[i]·d[i] must lie in [rhs-hi, rhs-lo] of the rest
+		from, to, ai := rhs-hi[k+1], rhs-lo[k+1], a[i]
+		if ai < 0 {
+			from, to, ai = -to, -from, -ai
+		}
+		first := max(0, -floorDiv(-from, ai))
+		last := min(spans[i], floorDiv(to, ai))
+		for v := first; v <= last; v++ {
+			d[i] = v
+			if solve(k+1, rhs-a[i]*v) {
+				return true
+			}
+		}
+		return false
+	}
+	return solve(0, rhs)
+}
+
+// monotone checks that the result grows or shrinks steadily with the
+// parameter of the largest range, sampled at five points while the others
+// are held at their minimum and at their maximum. If so, that parameter is
+// binary searched for every combination of the others.
+func (s *seeker) monotone(workers int) ([]int, bool) {
+	inner := 0
+	for i, p := range s.params {
+		if p.span() > s.params[inner].span() {
+			inner = i
+		}
+	}
+	dir, ok := s.direction(inner)
+	if !ok || dir == 0 {
+		// constant in the largest range, no faster than brute force
+		return nil, false
+	}
+	p := s.params[inner]
+	return s.parallel(workers, inner, func(ic *Intcode, values []int) bool {
+		// first v where the result reaches want in the search direction
+		lo, hi := p.Min, p.Max+1
+		for lo < hi {
+			values[inner] = lo + (hi-lo)/2
+			f, ok := s.eval(ic, values)
+			if !ok {
+				return false
+			}
+			if f*dir >= s.want*dir {
+				hi = values[inner]
+			} else {
+				lo = values[inner] + 1
+			}
+		}
+		if lo > p.Max {
+			return false
+		}
+		values[inner] = lo
+		f, ok := s.eval(ic, values)
+		return ok && f == s.want
+	})
+}
+
+// direction returns 1 if the result never shrinks with parameter i, -1 if
+// it never grows and 0 if it is constant in the samples.
+func (s *seeker) direction(i int) (int, bool) {
+	p := s.params[i]
+	dir := 0
+	for _, others := range []func(Param) int{
+		func(p Param) int { return p.Min },
+		func(p Param) int { return p.Max },
+	} {
+		values := make([]int, len(s.params))
+		for j, q := range s.params {
+			values[j] = others(q)
+		}
+		prev := 0
+		for k := range 5 {
+			values[i] = p.Min + p.span()*k/4
+			f, ok := s.eval(s.ic, values)
+			if !ok {
+				return 0, false
+			}
+			if k > 0 {
+				d := sign(f - prev)
+				if d != 0 && dir != 0 && d != dir {
+					return 0, false
+				}
+				if d != 0 {
+					dir = d
+				}
+			}
+			prev = f
+		}
+	}
+	return dir, true
+}
+
+// bruteForce tries all combinations.
+func (s *seeker) bruteForce(workers int) ([]int, bool) {
+	return s.parallel(workers, -1, func(ic *Intcode, values []int) bool {
+		f, ok := s.eval(ic, values)
+		return ok && f == s.want
+	})
+}
+
+// parallel calls try for all combinations of the parameters except skip,
+// using the given number of workers. try may set the value of parameter
+// skip. The combination with the smallest values in order of params that
+// try accepts is returned.
+func (s *seeker) parallel(workers, skip int, try func(ic *Intcode, values []int) bool) ([]int, bool) {
+	n := 1
+	for i, p := range s.params {
+		if i == skip {
+			continue
+		}
+		if n > (1<<62)/(p.span()+1) {
+			return nil, false
+		}
+		n *= p.span() + 1
+	}
+	// decode returns combination i, the last parameter varies fastest.
+	decode := func(i int, values []int) {
+		for j := len(s.params) - 1; j >= 0; j-- {
+			p := s.params[j]
+			if j == skip {
+				values[j] = p.Min
+				continue
+			}
+			values[j] = p.Min + i%(p.span()+1)
+			i /= p.span() + 1
+		}
+	}
+
+	var (
+		next, best atomic.Int64
+		mu         sync.Mutex
+		sol        []int
+		wg         sync.WaitGroup
+	)
+	best.Store(int64(n))
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ic := s.ic.Clone()
+			values := make([]int, len(s.params))
+			for {
+				i := int(next.Add(1) - 1)
+				// combinations after a solution cannot win
+				if i >= int(best.Load()) {
+					return
+				}
+				decode(i, values)
+				if !try(ic, values) {
+					continue
+				}
+				mu.Lock()
+				if i < int(best.Load()) {
+					best.Store(int64(i))
+					sol = slices.Clone(values)
+				}
+				mu.Unlock()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+	return sol, sol != nil
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
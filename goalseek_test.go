@@ -0,0 +1,110 @@
+package adventofcode2019
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGoalSeek(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		params  []Param
+		want    int
+		values  []int
+		method  seekMethod
+	}{
+		// mem[0] = 3·x + y
+		{"linear", "1102,3,0,0,1001,0,0,0,99", []Param{{2, 0, 99}, {6, 0, 9}}, 131,
+			[]int{41, 8}, seekLinear},
+		// mem[0] = x² + y
+		{"monotone", "1101,0,0,13,2,13,13,0,1001,0,0,0,99,0", []Param{{2, 0, 1000}, {10, 0, 9}}, 123208,
+			[]int{351, 7}, seekMonotone},
+		// mem[0] = x·y
+		{"brute force", "1102,0,0,0,99", []Param{{1, -5, 5}, {2, -5, 5}}, 6,
+			[]int{-3, -2}, seekBruteForce},
+		// opcodes 0 and 3 fault without input
+		{"faults", "1,0,0,0,99", []Param{{0, 0, 3}}, 4,
+			[]int{2}, seekBruteForce},
+		// negative addresses and addresses past the end
+		{"addresses", "1,0,0,0,99", []Param{{1, -3, 200}}, 2,
+			[]int{0}, seekBruteForce},
+		// jump back to the start forever unless x is 0, then mem[0] = 7
+		{"loops", "1105,0,0,1101,4,3,0,99", []Param{{1, -2, 2}}, 7,
+			[]int{0}, seekBruteForce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ic, err := NewIntcode([]byte(tt.program))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, workers := range []int{1, 4} {
+				values, method, err := goalSeek(ic, tt.params, 0, tt.want, workers)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.Equal(tt.values, values) || tt.method != method {
+					t.Fatalf("want %v by method %d but got %v by method %d",
+						tt.values, tt.method, values, method)
+				}
+			}
+		})
+	}
+}
+
+func TestGoalSeekErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []Param
+		want   string
+	}{
+		{"no params", nil, "no parameters"},
+		{"empty range", []Param{{1, 0, 9}, {2, 3, 2}}, "parameter 1: want min <= max but got 3 > 2"},
+		{"address", []Param{{1, 0, 9}, {-1, 0, 9}}, "parameter 1: address -1 out of memory"},
+		{"no solution", []Param{{1, 0, 9}, {2, 0, 9}}, "no parameters leave 1000 at address 0"},
+	}
+	ic, err := NewIntcode([]byte("1102,0,0,0,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GoalSeek(ic, tt.params, 0, 1000)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("want error %q but got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDay02GoalSeekLinear(t *testing.T) {
+	ic, err := NewIntcode(fileFromFilename(t, filename, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, method, err := goalSeek(ic, []Param{{1, 0, 99}, {2, 0, 99}}, 0, 19690720, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{82, 98}; !slices.Equal(want, values) || method != seekLinear {
+		t.Fatalf("want %v by linear method but got %v by method %d", want, values, method)
+	}
+}
+
+// TestGoalSeekCoverage runs parallel workers on a machine with coverage, go
+// test -race finds them if they share it.
+func TestGoalSeekCoverage(t *testing.T) {
+	ic, err := NewIntcode([]byte("1102,0,0,0,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := NewCoverage()
+	ic.Cover(cov)
+	if _, _, err := goalSeek(ic, []Param{{1, -5, 5}, {2, -5, 5}}, 0, 6, 4); err != nil {
+		t.Fatal(err)
+	}
+	if n := cov.Hits(0); n != 0 {
+		t.Fatalf("want goal seek not covered but got %d hits", n)
+	}
+}
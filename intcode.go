@@ -1,31 +1,39 @@
 package adventofcode2019
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
-// state represents the current state of the Intcode machine after a Step.
-type state int
+// State represents the current state of the Intcode machine after a Step.
+type State int
 
 const (
-	running    state = iota // Still executing, call Step again
-	needsInput              // Waiting for input, call Input then Step
-	hasOutput               // Output available, call Output then Step
-	halted                  // Program finished (opcode 99)
+	Running    State = iota // Still executing, call Step again
+	NeedsInput              // Waiting for input, call Input then Step
+	HasOutput               // Output available, call Output then Step
+	Halted                  // Program finished (opcode 99)
+	Faulted                 // Illegal instruction or memory access, see Err
 )
 
-// intcode is a synchronous intcode virtual machine.
+// Intcode is a synchronous Intcode virtual machine.
 // Use Step for fine-grained control or Run for batch execution.
-type intcode struct {
-	original []int // pristine copy for Reset
-	mem      []int // working memory
-	ip       int   // instruction pointer
-	relBase  int   // relative base for mode 2
-	output   int   // last output value
-	state    state // current state
-	dirty    bool  // true if program memory was modified
+type Intcode struct {
+	original []int          // pristine copy for Reset
+	mem      []int          // working memory
+	ip       int            // instruction pointer
+	relBase  int            // relative base for mode 2
+	output   int            // last output value
+	state    State          // current state
+	dirty    bool           // true if program memory was modified
+	err      error          // reason for Faulted state
+	ext      map[int]Opcode // extension opcodes, see Register
+	cov      *Coverage      // optional execution counter, see Cover
+	outbuf   []int          // output buffer reused by RunUntilInput
 }
 
-// newIntcode parses the input and returns a new Intcode machine.
-func newIntcode(input []byte) (*intcode, error) {
+// NewIntcode parses the input and returns a new Intcode machine.
+func NewIntcode(input []byte) (*Intcode, error) {
 	// Count commas to pre-allocate
 	count := 1
 	for _, b := range input {
@@ -65,7 +73,7 @@ func newIntcode(input []byte) (*intcode, error) {
 		original = append(original, num)
 	}
 
-	ic := &intcode{
+	ic := &Intcode{
 		original: original,
 		mem:      make([]int, len(original)),
 	}
@@ -74,7 +82,7 @@ func newIntcode(input []byte) (*intcode, error) {
 }
 
 // Reset restores the machine to its initial state.
-func (ic *intcode) Reset() {
+func (ic *Intcode) Reset() {
 	// Only copy memory if it was modified
 	if ic.dirty {
 		// Reuse existing memory if capacity is sufficient
@@ -89,91 +97,114 @@ func (ic *intcode) Reset() {
 	ic.ip = 0
 	ic.relBase = 0
 	ic.output = 0
-	ic.state = running
+	ic.state = Running
+	ic.err = nil
 }
 
 // Clone returns a fresh Intcode machine sharing the same parsed program.
-func (ic *intcode) Clone() *intcode {
-	clone := &intcode{
+func (ic *Intcode) Clone() *Intcode {
+	clone := &Intcode{
 		original: ic.original, // share original (never modified)
 		mem:      make([]int, len(ic.original)),
+		ext:      ic.ext, // copy-on-write, see Register
+		cov:      ic.cov,
 	}
 	copy(clone.mem, ic.original)
 	return clone
 }
 
-// Mem returns the value at memory address addr.
-func (ic *intcode) Mem(addr int) int {
-	if addr >= len(ic.mem) {
+// Mem returns the value at memory address addr, 0 outside of memory.
+func (ic *Intcode) Mem(addr int) int {
+	if addr < 0 || addr >= len(ic.mem) {
 		return 0
 	}
 	return ic.mem[addr]
 }
 
-// SetMem sets the value at memory address addr.
-func (ic *intcode) SetMem(addr, val int) {
+// SetMem sets the value at memory address addr, 0 <= addr < MaxMem.
+func (ic *Intcode) SetMem(addr, val int) {
 	ic.grow(addr)
 	ic.markDirty(addr)
 	ic.mem[addr] = val
 }
 
 // Output returns the last output value.
-func (ic *intcode) Output() int {
+func (ic *Intcode) Output() int {
 	return ic.output
 }
 
 // Input provides a value for the next input instruction.
-func (ic *intcode) Input(val int) {
-	if ic.state != needsInput {
+func (ic *Intcode) Input(val int) {
+	if ic.state != NeedsInput {
 		return
 	}
 	opcode := ic.mem[ic.ip] % 100
 	if opcode != 3 {
 		return
 	}
-	addr := ic.writeAddr(1)
-	ic.grow(addr)
-	ic.markDirty(addr)
-	ic.mem[addr] = val
+	ic.store(ic.writeAddr(1), val)
 	ic.ip += 2
-	ic.state = running
+	ic.state = Running
+}
+
+// Err returns the reason why the machine is in Faulted state, or nil.
+func (ic *Intcode) Err() error {
+	return ic.err
+}
+
+// fault stops the machine with a descriptive error.
+func (ic *Intcode) fault(err error) State {
+	ic.err = err
+	ic.state = Faulted
+	return ic.state
 }
 
 // Step executes one instruction and returns the new state.
-func (ic *intcode) Step() state {
-	if ic.state == halted || ic.state == needsInput {
+func (ic *Intcode) Step() State {
+	if ic.state == Halted || ic.state == NeedsInput || ic.state == Faulted {
 		return ic.state
 	}
+	if ic.ip < 0 || ic.ip >= len(ic.mem) {
+		return ic.fault(fmt.Errorf("instruction pointer %d out of memory", ic.ip))
+	}
+	if ic.cov != nil {
+		ic.cov.hit(ic.ip)
+	}
 
-	opcode := ic.mem[ic.ip] % 100
+	ip := ic.ip
+	opcode := ic.mem[ip] % 100
+	if uint(opcode) < uint(len(builtinArity)) && ip+builtinArity[opcode] >= len(ic.mem) {
+		return ic.fault(fmt.Errorf("instruction %d at address %d: %d parameters exceed memory",
+			ic.mem[ip], ip, builtinArity[opcode]))
+	}
 
+	// read and writeAddr record bad operands in ic.err, store then leaves
+	// memory alone and the instruction faults below
 	switch opcode {
 	case 1: // add
-		a := ic.read(1)
-		b := ic.read(2)
-		addr := ic.writeAddr(3)
-		ic.grow(addr)
-		ic.markDirty(addr)
-		ic.mem[addr] = a + b
+		a, b := ic.read(1), ic.read(2)
+		ic.store(ic.writeAddr(3), a+b)
 		ic.ip += 4
 
 	case 2: // multiply
-		a := ic.read(1)
-		b := ic.read(2)
-		addr := ic.writeAddr(3)
-		ic.grow(addr)
-		ic.markDirty(addr)
-		ic.mem[addr] = a * b
+		a, b := ic.read(1), ic.read(2)
+		ic.store(ic.writeAddr(3), a*b)
 		ic.ip += 4
 
 	case 3: // input
-		ic.state = needsInput
+		if ic.writeAddr(1); ic.err != nil {
+			break
+		}
+		ic.state = NeedsInput
 		return ic.state
 
 	case 4: // output
 		ic.output = ic.read(1)
+		if ic.err != nil {
+			break
+		}
 		ic.ip += 2
-		ic.state = hasOutput
+		ic.state = HasOutput
 		return ic.state
 
 	case 5: // jump-if-true
@@ -191,25 +222,13 @@ func (ic *intcode) Step() state {
 		}
 
 	case 7: // less than
-		addr := ic.writeAddr(3)
-		ic.grow(addr)
-		ic.markDirty(addr)
-		if ic.read(1) < ic.read(2) {
-			ic.mem[addr] = 1
-		} else {
-			ic.mem[addr] = 0
-		}
+		a, b := ic.read(1), ic.read(2)
+		ic.store(ic.writeAddr(3), boolean(a < b))
 		ic.ip += 4
 
 	case 8: // equals
-		addr := ic.writeAddr(3)
-		ic.grow(addr)
-		ic.markDirty(addr)
-		if ic.read(1) == ic.read(2) {
-			ic.mem[addr] = 1
-		} else {
-			ic.mem[addr] = 0
-		}
+		a, b := ic.read(1), ic.read(2)
+		ic.store(ic.writeAddr(3), boolean(a == b))
 		ic.ip += 4
 
 	case 9: // adjust relative base
@@ -217,11 +236,25 @@ func (ic *intcode) Step() state {
 		ic.ip += 2
 
 	case 99: // halt
-		ic.state = halted
+		ic.state = Halted
 		return ic.state
+
+	default:
+		op, ok := ic.ext[opcode]
+		if !ok {
+			return ic.fault(fmt.Errorf("illegal opcode %d at address %d",
+				ic.mem[ip], ip))
+		}
+		if err := ic.exec(op); err != nil {
+			return ic.fault(fmt.Errorf("opcode %d (%s) at address %d: %w",
+				op.Code, op.Name, ip, err))
+		}
 	}
 
-	ic.state = running
+	if ic.err != nil {
+		return ic.fault(fmt.Errorf("instruction %d at address %d: %w", ic.mem[ip], ip, ic.err))
+	}
+	ic.state = Running
 	return ic.state
 }
 
@@ -229,60 +262,84 @@ func (ic *intcode) Step() state {
 var errNeedsInput = errors.New("program needs input but none provided")
 
 // Run executes the program with the given inputs and returns all outputs.
-// Returns ErrNeedsInput if the program needs more inputs than provided.
-func (ic *intcode) Run(inputs ...int) ([]int, error) {
-	// Fast path for programs with no I/O (like Day 2)
-	if len(inputs) == 0 {
+// Returns errNeedsInput if the program needs more inputs than provided.
+func (ic *Intcode) Run(inputs ...int) ([]int, error) {
+	// Fast path for programs with no I/O (like Day 2), bypasses coverage
+	if len(inputs) == 0 && ic.cov == nil {
 		return ic.runNoIO()
 	}
+	return ic.runWithStep(inputs)
+}
 
-	inputIdx := 0
-	var outputs []int
+// RunUntilInput feeds inputs to the program and executes it until it halts,
+// faults, or needs more input than provided. It returns the outputs
+// produced and the resulting state. Running out of input is not an error:
+// the machine stays in NeedsInput state and can be resumed by calling
+// RunUntilInput again with the next inputs.
+// The returned outputs are only valid until the next call of RunUntilInput.
+func (ic *Intcode) RunUntilInput(inputs ...int) ([]int, State, error) {
+	outputs, state, err := ic.runUntilInput(ic.outbuf[:0], inputs)
+	ic.outbuf = outputs
+	return outputs, state, err
+}
 
+// runUntilInput implements RunUntilInput, appending to outputs.
+func (ic *Intcode) runUntilInput(outputs []int, inputs []int) ([]int, State, error) {
+	inputIdx := 0
 	for {
 		state := ic.Step()
 		switch state {
-		case halted:
-			return outputs, nil
-		case needsInput:
-			if inputIdx < len(inputs) {
-				ic.Input(inputs[inputIdx])
-				inputIdx++
-			} else {
-				return outputs, errNeedsInput
+		case Halted:
+			return outputs, state, nil
+		case NeedsInput:
+			if inputIdx == len(inputs) {
+				return outputs, state, nil
 			}
-		case hasOutput:
+			ic.Input(inputs[inputIdx])
+			inputIdx++
+		case HasOutput:
 			outputs = append(outputs, ic.output)
-			ic.state = running
+			ic.state = Running
+		case Faulted:
+			return outputs, state, ic.err
 		}
 	}
 }
 
 // runNoIO is an optimized path for programs without input/output.
-func (ic *intcode) runNoIO() ([]int, error) {
+func (ic *Intcode) runNoIO() ([]int, error) {
 	mem := ic.mem
 	ip := 0
 
 	for {
+		// Leave instructions near the end of memory and addresses outside
+		// of it to Step, which grows memory or faults
+		if ip+3 >= len(mem) {
+			ic.ip = ip
+			return ic.runWithStep(nil)
+		}
 		op := mem[ip]
 		opcode := op % 100
 
 		// Fast path for mode 0 (position mode) - most common case
 		if op < 100 {
 			switch opcode {
-			case 1: // add
-				addr := mem[ip+3]
-				ic.markDirty(addr)
-				mem[addr] = mem[mem[ip+1]] + mem[mem[ip+2]]
-				ip += 4
-			case 2: // multiply
-				addr := mem[ip+3]
+			case 1, 2:
+				x, y, addr := mem[ip+1], mem[ip+2], mem[ip+3]
+				if uint(x) >= uint(len(mem)) || uint(y) >= uint(len(mem)) || uint(addr) >= uint(len(mem)) {
+					ic.ip = ip
+					return ic.runWithStep(nil)
+				}
 				ic.markDirty(addr)
-				mem[addr] = mem[mem[ip+1]] * mem[mem[ip+2]]
+				if opcode == 1 {
+					mem[addr] = mem[x] + mem[y]
+				} else {
+					mem[addr] = mem[x] * mem[y]
+				}
 				ip += 4
 			case 99:
 				ic.ip = ip
-				ic.state = halted
+				ic.state = Halted
 				return nil, nil
 			default:
 				ic.ip = ip
@@ -291,134 +348,99 @@ func (ic *intcode) runNoIO() ([]int, error) {
 			continue
 		}
 
-		// Slow path with mode handling
-		switch opcode {
-		case 1: // add
-			a := ic.readAt(ip, 1)
-			b := ic.readAt(ip, 2)
-			addr := ic.writeAddrAt(ip, 3)
-			ic.grow(addr)
-			ic.markDirty(addr)
-			mem = ic.mem
-			mem[addr] = a + b
-			ip += 4
-		case 2: // multiply
-			a := ic.readAt(ip, 1)
-			b := ic.readAt(ip, 2)
-			addr := ic.writeAddrAt(ip, 3)
-			ic.grow(addr)
-			ic.markDirty(addr)
-			mem = ic.mem
-			mem[addr] = a * b
-			ip += 4
-		case 99:
-			ic.ip = ip
-			ic.state = halted
-			return nil, nil
-		default:
-			ic.ip = ip
-			return ic.runWithStep(nil)
-		}
+		// Modes, I/O and jumps are left to Step
+		ic.ip = ip
+		return ic.runWithStep(nil)
 	}
 }
 
 // runWithStep continues execution using Step() for complex programs.
-func (ic *intcode) runWithStep(inputs []int) ([]int, error) {
-	inputIdx := 0
-	var outputs []int
-
-	for {
-		state := ic.Step()
-		switch state {
-		case halted:
-			return outputs, nil
-		case needsInput:
-			if inputIdx < len(inputs) {
-				ic.Input(inputs[inputIdx])
-				inputIdx++
-			} else {
-				return outputs, errNeedsInput
-			}
-		case hasOutput:
-			outputs = append(outputs, ic.output)
-			ic.state = running
-		}
+func (ic *Intcode) runWithStep(inputs []int) ([]int, error) {
+	outputs, state, err := ic.runUntilInput(nil, inputs)
+	if state == NeedsInput {
+		return outputs, errNeedsInput
 	}
+	return outputs, err
 }
 
-// readAt reads parameter n at instruction pointer ip.
-func (ic *intcode) readAt(ip, n int) int {
-	mode := (ic.mem[ip] / pow10(n+1)) % 10
-	param := ic.mem[ip+n]
-	switch mode {
-	case 0:
-		if param >= len(ic.mem) {
-			return 0
-		}
-		return ic.mem[param]
-	case 1:
-		return param
-	case 2:
-		addr := ic.relBase + param
-		if addr >= len(ic.mem) {
-			return 0
-		}
-		return ic.mem[addr]
-	}
-	return 0
-}
+// MaxMem limits how far memory grows, addresses beyond fault the machine.
+const MaxMem = 1 << 24
 
-// writeAddrAt returns write address for parameter n at instruction pointer ip.
-func (ic *intcode) writeAddrAt(ip, n int) int {
-	mode := (ic.mem[ip] / pow10(n+1)) % 10
-	param := ic.mem[ip+n]
-	switch mode {
-	case 2:
-		return ic.relBase + param
-	default:
-		return param
+// builtinArity is the number of parameters of opcodes 1..9.
+var builtinArity = [10]int{1: 3, 2: 3, 3: 1, 4: 1, 5: 2, 6: 2, 7: 3, 8: 3, 9: 1}
+
+// operandError records the first bad operand of the current instruction.
+func (ic *Intcode) operandError(format string, args ...any) {
+	if ic.err == nil {
+		ic.err = fmt.Errorf(format, args...)
 	}
 }
 
-// read returns the value of parameter n based on its mode.
-func (ic *intcode) read(n int) int {
+// read returns the value of parameter n based on its mode. Addresses
+// beyond memory read 0.
+func (ic *Intcode) read(n int) int {
 	mode := (ic.mem[ic.ip] / pow10(n+1)) % 10
 	param := ic.mem[ic.ip+n]
 
 	switch mode {
 	case 0: // position
-		if param >= len(ic.mem) {
-			return 0
+		if uint(param) >= uint(len(ic.mem)) {
+			return ic.outside(param, n)
 		}
 		return ic.mem[param]
 	case 1: // immediate
 		return param
 	case 2: // relative
 		addr := ic.relBase + param
-		if addr >= len(ic.mem) {
-			return 0
+		if uint(addr) >= uint(len(ic.mem)) {
+			return ic.outside(addr, n)
 		}
 		return ic.mem[addr]
 	}
+	ic.operandError("illegal mode %d of parameter %d", mode, n)
+	return 0
+}
+
+// outside reads an address beyond memory, negative addresses are bad.
+func (ic *Intcode) outside(addr, n int) int {
+	if addr < 0 {
+		ic.operandError("address %d of parameter %d out of memory", addr, n)
+	}
 	return 0
 }
 
 // writeAddr returns the address where parameter n should write.
-func (ic *intcode) writeAddr(n int) int {
+func (ic *Intcode) writeAddr(n int) int {
 	mode := (ic.mem[ic.ip] / pow10(n+1)) % 10
-	param := ic.mem[ic.ip+n]
+	addr := ic.mem[ic.ip+n]
 
 	switch mode {
-	case 0: // position
-		return param
+	case 0, 1: // position, immediate writes like position
 	case 2: // relative
-		return ic.relBase + param
+		addr += ic.relBase
+	default:
+		ic.operandError("illegal mode %d of parameter %d", mode, n)
+		return 0
+	}
+	if uint(addr) >= MaxMem {
+		ic.operandError("address %d of parameter %d out of memory", addr, n)
+		return 0
+	}
+	return addr
+}
+
+// store writes val to addr unless the instruction has a bad operand.
+func (ic *Intcode) store(addr, val int) {
+	if ic.err != nil {
+		return
 	}
-	return param
+	ic.grow(addr)
+	ic.markDirty(addr)
+	ic.mem[addr] = val
 }
 
 // grow expands memory if needed.
-func (ic *intcode) grow(addr int) {
+func (ic *Intcode) grow(addr int) {
 	if addr >= len(ic.mem) {
 		newMem := make([]int, addr+1)
 		copy(newMem, ic.mem)
@@ -427,14 +449,15 @@ func (ic *intcode) grow(addr int) {
 }
 
 // markDirty sets the dirty flag if writing to original program space
-func (ic *intcode) markDirty(addr int) {
+func (ic *Intcode) markDirty(addr int) {
 	if addr < len(ic.original) {
 		ic.dirty = true
 	}
 }
 
-// pow10table for fast mode extraction.
-var pow10table = [5]int{1, 10, 100, 1000, 10000}
+// pow10table for fast mode extraction, large enough for maxArity parameters.
+var pow10table = [maxArity + 2]int{1, 10, 100, 1000, 10000, 100000, 1000000,
+	10000000, 100000000, 1000000000}
 
 // pow10 returns 10^n.
 func pow10(n int) int {
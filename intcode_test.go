@@ -0,0 +1,133 @@
+package adventofcode2019
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRunUntilInputResume(t *testing.T) {
+	// add two inputs, output the sum, repeat
+	ic, err := NewIntcode([]byte("3,13,3,14,1,13,14,15,4,15,1105,1,0,0,0,0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// starve after the first input, resume with the second
+	outputs, state, err := ic.RunUntilInput(1)
+	if err != nil || state != NeedsInput || len(outputs) != 0 {
+		t.Fatalf("want starvation without outputs but got %v, %v, %v", outputs, state, err)
+	}
+	outputs, state, err = ic.RunUntilInput(2, 3, 4)
+	if err != nil || state != NeedsInput {
+		t.Fatalf("want starvation but got %v, %v", state, err)
+	}
+	if want := []int{3, 7}; !slices.Equal(want, outputs) {
+		t.Fatalf("want %v but got %v", want, outputs)
+	}
+}
+
+func TestRunUntilInputFault(t *testing.T) {
+	ic, err := NewIntcode([]byte("104,1,42"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs, state, err := ic.RunUntilInput()
+	if state != Faulted || err == nil {
+		t.Fatalf("want fault but got %v, %v", state, err)
+	}
+	if want := []int{1}; !slices.Equal(want, outputs) {
+		t.Fatalf("want outputs before fault %v but got %v", want, outputs)
+	}
+}
+
+// TestIntcodeBoostConformance runs BOOST in test mode, which checks every
+// opcode and parameter mode, on each way the machine can be set up.
+func TestIntcodeBoostConformance(t *testing.T) {
+	const keycode = 2436480432
+	program := fileFromFilename(t, filename, 9)
+	tests := []struct {
+		name  string
+		setup func(ic *Intcode) error
+	}{
+		{"plain", func(*Intcode) error { return nil }},
+		{"coverage", func(ic *Intcode) error {
+			ic.Cover(NewCoverage())
+			return nil
+		}},
+		{"extension", func(ic *Intcode) error {
+			return ic.Register(Opcode{Code: 42, Name: "nop", Exec: func(*Intcode, []int) error {
+				return nil
+			}})
+		}},
+		{"reset", func(ic *Intcode) error {
+			// dirty memory and relative base of a previous run
+			if _, err := ic.Run(2); err != nil {
+				return err
+			}
+			ic.Reset()
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ic, err := NewIntcode(program)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.setup(ic); err != nil {
+				t.Fatal(err)
+			}
+			outputs, err := ic.Run(boostTestMode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := newBoostReport(outputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := r.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if r.Keycode != keycode {
+				t.Fatalf("want keycode %d but got %d", keycode, r.Keycode)
+			}
+		})
+	}
+}
+
+func TestIntcodeFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		want    string
+	}{
+		{"negative address", "1,-5,0,0,99",
+			"instruction 1 at address 0: address -5 of parameter 1 out of memory"},
+		{"negative relative address", "109,-10,22201,0,0,0,99",
+			"instruction 22201 at address 2: address -10 of parameter 1 out of memory"},
+		{"truncated", "1,0,0",
+			"instruction 1 at address 0: 3 parameters exceed memory"},
+		{"write too far", "1101,1,1,99999999,99",
+			"instruction 1101 at address 0: address 99999999 of parameter 3 out of memory"},
+		{"illegal mode", "304,0,99",
+			"instruction 304 at address 0: illegal mode 3 of parameter 1"},
+		{"negative input address", "3,-1,99",
+			"instruction 3 at address 0: address -1 of parameter 1 out of memory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ic, err := NewIntcode([]byte(tt.program))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// with input via Step, without input via the fast path
+			for _, inputs := range [][]int{{1}, nil} {
+				ic.Reset()
+				_, err = ic.Run(inputs...)
+				if err == nil || err.Error() != tt.want {
+					t.Fatalf("%v: want %q but got %v", inputs, tt.want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package bench parses the output of go test -bench.
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Result is one benchmark line.
+type Result struct {
+	Name        string // without Benchmark prefix and GOMAXPROCS suffix
+	NsPerOp     float64
+	BytesPerOp  uint64
+	AllocsPerOp uint64
+}
+
+// Output holds the environment and all results of one benchmark run. With
+// -count, the same name appears once per sample.
+type Output struct {
+	GOOS, GOARCH string
+	CPU          string
+	Results      []Result
+}
+
+// Parse reads the output of go test -bench -benchmem. Other lines such as
+// PASS or ok are ignored.
+func Parse(r io.Reader) (Output, error) {
+	var out Output
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "goos: "):
+			out.GOOS = strings.TrimSpace(strings.TrimPrefix(line, "goos: "))
+		case strings.HasPrefix(line, "goarch: "):
+			out.GOARCH = strings.TrimSpace(strings.TrimPrefix(line, "goarch: "))
+		case strings.HasPrefix(line, "cpu: "):
+			out.CPU = strings.TrimSpace(strings.TrimPrefix(line, "cpu: "))
+		case strings.HasPrefix(line, "Benchmark"):
+			res, err := parseResult(line)
+			if err != nil {
+				return out, fmt.Errorf("line %d: %w", n, err)
+			}
+			out.Results = append(out.Results, res)
+		}
+	}
+	return out, sc.Err()
+}
+
+// parseResult parses a line such as
+// BenchmarkDay01Part1-16  1523121  805.2 ns/op  0 B/op  0 allocs/op
+func parseResult(line string) (Result, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields)%2 != 0 {
+		return Result{}, fmt.Errorf("want name, iterations and value/unit pairs but got %q", line)
+	}
+	res := Result{Name: trimProcs(strings.TrimPrefix(fields[0], "Benchmark"))}
+	for i := 2; i < len(fields); i += 2 {
+		var err error
+		switch fields[i+1] {
+		case "ns/op":
+			res.NsPerOp, err = strconv.ParseFloat(fields[i], 64)
+		case "B/op":
+			res.BytesPerOp, err = strconv.ParseUint(fields[i], 10, 64)
+		case "allocs/op":
+			res.AllocsPerOp, err = strconv.ParseUint(fields[i], 10, 64)
+		}
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", fields[i+1], err)
+		}
+	}
+	return res, nil
+}
+
+// trimProcs removes the -N GOMAXPROCS suffix from a benchmark name.
+func trimProcs(name string) string {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+// SanitizeCPUName turns a CPU name into something usable in a filename:
+// spaces become dashes, anything but letters, digits, '@', '_' and '-' is
+// dropped.
+func SanitizeCPUName(input string) string {
+	var result strings.Builder
+
+	for _, r := range input {
+		switch {
+		case r == ' ':
+			result.WriteRune('-')
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			result.WriteRune(r)
+		case r == '@' || r == '_' || r == '-':
+			result.WriteRune(r)
+		default:
+			// Skip any other character
+		}
+	}
+
+	return result.String()
+}
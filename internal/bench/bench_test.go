@@ -0,0 +1,53 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const input = `goos: linux
+goarch: amd64
+pkg: gitlab.com/jhinrichsen/adventofcode2019
+cpu: Intel(R) Xeon(R) CPU @ 2.60GHz
+BenchmarkDay01Part1-16    	 1523121	       805.2 ns/op	       0 B/op	       0 allocs/op
+BenchmarkDay02Part2       	     230	   5200054 ns/op	10622774 B/op	    8299 allocs/op
+PASS
+ok  	gitlab.com/jhinrichsen/adventofcode2019	227.080s
+`
+	out, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.GOOS != "linux" || out.GOARCH != "amd64" || out.CPU != "Intel(R) Xeon(R) CPU @ 2.60GHz" {
+		t.Fatalf("unexpected environment %+v", out)
+	}
+	want := []Result{
+		{"Day01Part1", 805.2, 0, 0},
+		{"Day02Part2", 5200054, 10622774, 8299},
+	}
+	if len(out.Results) != len(want) {
+		t.Fatalf("want %d results but got %d", len(want), len(out.Results))
+	}
+	for i := range want {
+		if want[i] != out.Results[i] {
+			t.Fatalf("want %+v but got %+v", want[i], out.Results[i])
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse(strings.NewReader("cpu: x\nBenchmarkDay01Part1-16 10 fast ns/op\n"))
+	want := `line 2: ns/op: strconv.ParseFloat: parsing "fast": invalid syntax`
+	if err == nil || err.Error() != want {
+		t.Fatalf("want error %q but got %v", want, err)
+	}
+}
+
+func TestSanitizeCPUName(t *testing.T) {
+	want := "IntelR-XeonR-CPU-@-260GHz"
+	got := SanitizeCPUName("Intel(R) Xeon(R) CPU @ 2.60GHz")
+	if want != got {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
@@ -0,0 +1,60 @@
+package bench
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Env describes the machine and toolchain a run was measured on.
+type Env struct {
+	GOOS   string `json:"goos"`
+	GOARCH string `json:"goarch"`
+	Go     string `json:"go"`
+	CPU    string `json:"cpu"` // sanitized, as in cmd/cpuname
+}
+
+// CurrentEnv returns the environment of the running process.
+func CurrentEnv() Env {
+	return Env{
+		GOOS:   runtime.GOOS,
+		GOARCH: runtime.GOARCH,
+		Go:     runtime.Version(),
+		CPU:    SanitizeCPUName(cpuName()),
+	}
+}
+
+// cpuName returns the CPU model the same way go test -bench does on Linux,
+// or "unknown".
+func cpuName() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return "unknown"
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return "unknown"
+}
+
+// Record is the JSON representation of one solved part. Durations are in
+// nanoseconds, Allocs and Bytes are the allocations while solving, parsing
+// is accounted separately because both parts share the parsed input.
+type Record struct {
+	Day         int    `json:"day"`
+	Part        int    `json:"part"`
+	Answer      string `json:"answer,omitempty"`
+	ParseNs     int64  `json:"parse_ns"`
+	SolveNs     int64  `json:"solve_ns"`
+	ParseAllocs uint64 `json:"parse_allocs"`
+	ParseBytes  uint64 `json:"parse_bytes"`
+	Allocs      uint64 `json:"allocs"`
+	Bytes       uint64 `json:"bytes"`
+	Env
+}
@@ -0,0 +1,103 @@
+package bench
+
+import (
+	"math"
+	"slices"
+)
+
+// UTest returns the two-sided p-value of the Mann-Whitney U test, the same
+// test benchstat uses to decide whether two sets of samples differ. Small
+// samples without ties use the exact distribution of U, others the normal
+// approximation with tie correction. Empty samples are never significant.
+func UTest(x, y []float64) float64 {
+	m, n := len(x), len(y)
+	if m == 0 || n == 0 {
+		return 1
+	}
+
+	// rank all samples, ties get their average rank
+	type sample struct {
+		v     float64
+		fromX bool
+	}
+	all := make([]sample, 0, m+n)
+	for _, v := range x {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range y {
+		all = append(all, sample{v, false})
+	}
+	slices.SortFunc(all, func(a, b sample) int {
+		switch {
+		case a.v < b.v:
+			return -1
+		case a.v > b.v:
+			return 1
+		}
+		return 0
+	})
+	var rx, ties float64
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromX {
+				rx += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	u := rx - float64(m*(m+1))/2
+	u = min(u, float64(m*n)-u)
+
+	if ties == 0 && m+n <= 50 {
+		return min(1, 2*exactU(m, n, int(u)))
+	}
+
+	N := float64(m + n)
+	sigma := math.Sqrt(float64(m*n) / 12 * ((N + 1) - ties/(N*(N-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := (u - float64(m*n)/2 + 0.5) / sigma
+	return min(1, math.Erfc(-z/math.Sqrt2))
+}
+
+// exactU returns P(U <= u) for samples of size m and n without ties.
+func exactU(m, n, u int) float64 {
+	// f[i][j][k] is the number of orderings of i x and j y samples that
+	// have U = k, f(i, j, k) = f(i-1, j, k-j) + f(i, j-1, k).
+	f := make([][][]float64, m+1)
+	for i := range f {
+		f[i] = make([][]float64, n+1)
+		for j := range f[i] {
+			f[i][j] = make([]float64, i*j+1)
+			if i == 0 || j == 0 {
+				f[i][j][0] = 1
+				continue
+			}
+			for k := range f[i][j] {
+				if k-j >= 0 && k-j < len(f[i-1][j]) {
+					f[i][j][k] += f[i-1][j][k-j]
+				}
+				if k < len(f[i][j-1]) {
+					f[i][j][k] += f[i][j-1][k]
+				}
+			}
+		}
+	}
+	var le, total float64
+	for k, c := range f[m][n] {
+		if k <= u {
+			le += c
+		}
+		total += c
+	}
+	return le / total
+}
@@ -0,0 +1,30 @@
+package bench
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUTest(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		// 2 of 252 orderings are as extreme
+		{"separated", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252},
+		{"identical", []float64{5, 5, 5}, []float64{5, 5, 5}, 1},
+		{"single", []float64{1}, []float64{2}, 1},
+		{"empty", nil, []float64{2}, 1},
+		// ties use the normal approximation
+		{"ties", []float64{1, 1, 2, 2, 3}, []float64{4, 4, 5, 5, 6}, 0.0112},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UTest(tt.x, tt.y)
+			if math.Abs(tt.want-got) > 1e-4 {
+				t.Fatalf("want %.4f but got %.4f", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package inputdir finds puzzle inputs on disk.
+//
+// Each day has a main input dayNN.txt and any number of named inputs
+// dayNN_name.txt, e.g. day14_example1.txt. Inputs are searched in a list of
+// directories, the first match wins.
+package inputdir
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Main is the name of the main puzzle input of a day.
+const Main = "main"
+
+// EnvDir is the environment variable holding input directories, separated
+// by os.PathListSeparator.
+const EnvDir = "AOC_INPUT_DIR"
+
+// Input is a puzzle input file.
+type Input struct {
+	Day  int
+	Name string
+	Path string
+}
+
+// MissingError reports an input that is in none of the directories.
+type MissingError struct {
+	Day   int
+	Name  string
+	Tried []string
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("no %s input for day %02d, tried %s (set $%s to add directories)",
+		e.Name, e.Day, strings.Join(e.Tried, ", "), EnvDir)
+}
+
+// Unwrap makes errors.Is(err, fs.ErrNotExist) true.
+func (e *MissingError) Unwrap() error {
+	return fs.ErrNotExist
+}
+
+// Dirs returns the default directories: all entries of $AOC_INPUT_DIR, the
+// user cache directory ($XDG_CACHE_HOME/adventofcode2019 on Linux), and
+// testdata.
+func Dirs() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(EnvDir)) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if cache, err := os.UserCacheDir(); err == nil {
+		dirs = append(dirs, filepath.Join(cache, "adventofcode2019"))
+	}
+	return append(dirs, "testdata")
+}
+
+// Filename returns the file name of a named input of day.
+func Filename(day int, name string) string {
+	if name == Main {
+		return fmt.Sprintf("day%02d.txt", day)
+	}
+	return fmt.Sprintf("day%02d_%s.txt", day, name)
+}
+
+// parseFilename returns day and name of an input file name.
+func parseFilename(filename string) (day int, name string, ok bool) {
+	base, ok := strings.CutSuffix(filename, ".txt")
+	if !ok || len(base) < len("day00") || !strings.HasPrefix(base, "day") {
+		return 0, "", false
+	}
+	day, err := strconv.Atoi(base[3:5])
+	if err != nil || day < 1 || day > 25 {
+		return 0, "", false
+	}
+	switch rest := base[5:]; {
+	case rest == "":
+		return day, Main, true
+	case strings.HasPrefix(rest, "_") && len(rest) > 1:
+		return day, rest[1:], true
+	}
+	return 0, "", false
+}
+
+// Resolver finds inputs in a list of directories.
+type Resolver struct {
+	Dirs []string
+}
+
+// NewResolver returns a resolver for the default directories.
+func NewResolver() Resolver {
+	return Resolver{Dirs: Dirs()}
+}
+
+// Find returns the first input of day with name.
+func (r Resolver) Find(day int, name string) (Input, error) {
+	filename := Filename(day, name)
+	var tried []string
+	for _, dir := range r.Dirs {
+		path := filepath.Join(dir, filename)
+		_, err := os.Stat(path)
+		if err == nil {
+			return Input{day, name, path}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return Input{}, err
+		}
+		tried = append(tried, path)
+	}
+	return Input{}, &MissingError{day, name, tried}
+}
+
+// Read returns the content of the first input of day with name.
+func (r Resolver) Read(day int, name string) ([]byte, error) {
+	in, err := r.Find(day, name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(in.Path)
+}
+
+// List returns all inputs in all directories ordered by day, main input
+// first, then by name. Inputs hidden by an earlier directory are left out.
+// Missing directories are skipped.
+func (r Resolver) List() ([]Input, error) {
+	seen := make(map[string]bool)
+	var inputs []Input
+	for _, dir := range r.Dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			day, name, ok := parseFilename(e.Name())
+			if !ok || e.IsDir() || seen[e.Name()] {
+				continue
+			}
+			seen[e.Name()] = true
+			inputs = append(inputs, Input{day, name, filepath.Join(dir, e.Name())})
+		}
+	}
+	slices.SortFunc(inputs, func(a, b Input) int {
+		if a.Day != b.Day {
+			return a.Day - b.Day
+		}
+		if (a.Name == Main) != (b.Name == Main) {
+			if a.Name == Main {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return inputs, nil
+}
+
+// Days returns the days that have a main input.
+func (r Resolver) Days() ([]int, error) {
+	inputs, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	var days []int
+	for _, in := range inputs {
+		if in.Name == Main {
+			days = append(days, in.Day)
+		}
+	}
+	return days, nil
+}
@@ -0,0 +1,112 @@
+package inputdir
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(dir), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeFiles(t, first, "day14_example1.txt", "notes.txt")
+	writeFiles(t, second, "day14.txt", "day14_example1.txt", "day02.txt", "day08-part2-result.txt")
+	r := Resolver{Dirs: []string{first, second, filepath.Join(t.TempDir(), "nonexistent")}}
+
+	in, err := r.Find(14, "example1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(first, "day14_example1.txt"); want != in.Path {
+		t.Fatalf("want %s but got %s", want, in.Path)
+	}
+	buf, err := r.Read(14, Main)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != second {
+		t.Fatalf("want main input from %s but got %s", second, buf)
+	}
+
+	inputs, err := r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, in := range inputs {
+		got = append(got, fmt.Sprintf("%d %s", in.Day, in.Name))
+	}
+	if want := []string{"2 main", "14 main", "14 example1"}; !slices.Equal(want, got) {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+
+	days, err := r.Days()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 14}; !slices.Equal(want, days) {
+		t.Fatalf("want %v but got %v", want, days)
+	}
+}
+
+func TestResolverMissing(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Resolver{Dirs: []string{dir}}.Find(3, Main)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("want not exist error but got %v", err)
+	}
+	want := fmt.Sprintf("no main input for day 03, tried %s (set $AOC_INPUT_DIR to add directories)",
+		filepath.Join(dir, "day03.txt"))
+	if err.Error() != want {
+		t.Fatalf("want %q but got %q", want, err)
+	}
+}
+
+func TestDirs(t *testing.T) {
+	t.Setenv(EnvDir, "a"+string(filepath.ListSeparator)+"b")
+	t.Setenv("XDG_CACHE_HOME", "/cache")
+	t.Setenv("HOME", "/home")
+	dirs := Dirs()
+	if len(dirs) < 3 || dirs[0] != "a" || dirs[1] != "b" || dirs[len(dirs)-1] != "testdata" {
+		t.Fatalf("want a, b, ..., testdata but got %q", dirs)
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		day      int
+		name     string
+		ok       bool
+	}{
+		{"day01.txt", 1, Main, true},
+		{"day10_part2_example1.txt", 10, "part2_example1", true},
+		{"day26.txt", 0, "", false},
+		{"day08-part2-result.txt", 0, "", false},
+		{"day01_.txt", 0, "", false},
+		{"answers.txt", 0, "", false},
+		{"day01.pbm", 0, "", false},
+	}
+	for _, tt := range tests {
+		day, name, ok := parseFilename(tt.filename)
+		if tt.day != day || tt.name != name || tt.ok != ok {
+			t.Fatalf("%s: want %d %q %v but got %d %q %v", tt.filename,
+				tt.day, tt.name, tt.ok, day, name, ok)
+		}
+		if ok && Filename(day, name) != tt.filename {
+			t.Fatalf("want %s but got %s", tt.filename, Filename(day, name))
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package ocr reads the block letters that the visual puzzles render, such as
+// the image of day 8 and the hull of day 11.
+package ocr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// glyphHeight is the height of the block letters, in pixels.
+const glyphHeight = 6
+
+// glyphPitch is the distance of letters in a line. Letters up to 4 pixels
+// wide keep a blank column to the next one, 5 pixel wide letters touch it.
+const glyphPitch = 5
+
+// adventGlyphs is the block letter font used by the visual puzzles. Glyphs
+// are stored without blank columns, most letters are 4 pixels wide.
+var adventGlyphs = []struct {
+	letter byte
+	rows   [glyphHeight]string
+}{
+	{'A', [...]string{".##.", "#..#", "#..#", "####", "#..#", "#..#"}},
+	{'B', [...]string{"###.", "#..#", "###.", "#..#", "#..#", "###."}},
+	{'C', [...]string{".##.", "#..#", "#...", "#...", "#..#", ".##."}},
+	{'E', [...]string{"####", "#...", "###.", "#...", "#...", "####"}},
+	{'F', [...]string{"####", "#...", "###.", "#...", "#...", "#..."}},
+	{'G', [...]string{".##.", "#..#", "#...", "#.##", "#..#", ".###"}},
+	{'H', [...]string{"#..#", "#..#", "####", "#..#", "#..#", "#..#"}},
+	{'I', [...]string{"###", ".#.", ".#.", ".#.", ".#.", "###"}},
+	{'J', [...]string{"..##", "...#", "...#", "...#", "#..#", ".##."}},
+	{'K', [...]string{"#..#", "#.#.", "##..", "#.#.", "#.#.", "#..#"}},
+	{'L', [...]string{"#...", "#...", "#...", "#...", "#...", "####"}},
+	{'O', [...]string{".##.", "#..#", "#..#", "#..#", "#..#", ".##."}},
+	{'P', [...]string{"###.", "#..#", "#..#", "###.", "#...", "#..."}},
+	{'R', [...]string{"###.", "#..#", "#..#", "###.", "#.#.", "#..#"}},
+	{'S', [...]string{".###", "#...", "#...", ".##.", "...#", "###."}},
+	{'U', [...]string{"#..#", "#..#", "#..#", "#..#", "#..#", ".##."}},
+	{'Y', [...]string{"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#.."}},
+	{'Z', [...]string{"####", "...#", "..#.", ".#..", "#...", "####"}},
+}
+
+// adventFont maps the concatenated rows of a glyph to its letter. Glyphs of
+// different width have keys of different length, so they cannot collide.
+var adventFont = func() map[string]byte {
+	m := make(map[string]byte, len(adventGlyphs))
+	for _, g := range adventGlyphs {
+		m[strings.Join(g.rows[:], "")] = g.letter
+	}
+	return m
+}()
+
+// Glyph is a letter cut out of a bitmap, the lit columns between two blank
+// ones.
+type Glyph struct {
+	X    int      // leftmost column in the bitmap
+	Rows []string // one per row, '#' for lit and '.' for dark pixels
+}
+
+// String returns the rows separated by spaces.
+func (a Glyph) String() string {
+	return strings.Join(a.Rows, " ")
+}
+
+// UnknownGlyphError reports a glyph that is not in the font.
+type UnknownGlyphError struct {
+	Glyph
+}
+
+func (e *UnknownGlyphError) Error() string {
+	return fmt.Sprintf("unknown glyph at column %d: %s", e.X, e.Glyph)
+}
+
+// Segment cuts a line of block letters out of an image of the given size,
+// lit reports if the pixel at (x, y) is lit. The line starts at the topmost
+// lit pixel, letters are separated by blank columns. Lit columns wider than
+// a letter are cut every glyphPitch columns.
+func Segment(width, height int, lit func(x, y int) bool) ([]Glyph, error) {
+	minX, minY, maxX, maxY := width, height, -1, -1
+	for y := range height {
+		for x := range width {
+			if lit(x, y) {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < 0 {
+		return nil, fmt.Errorf("no letters in %dx%d image", width, height)
+	}
+	if maxY-minY >= glyphHeight {
+		return nil, fmt.Errorf("want letters %d pixels tall but got %d", glyphHeight, maxY-minY+1)
+	}
+
+	blank := func(x int) bool {
+		for y := minY; y <= maxY; y++ {
+			if lit(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+	var glyphs []Glyph
+	for x := minX; x <= maxX; x++ {
+		if blank(x) {
+			continue
+		}
+		from := x
+		for x < maxX && !blank(x+1) && x+1-from < glyphPitch {
+			x++
+		}
+		g := Glyph{X: from, Rows: make([]string, glyphHeight)}
+		row := make([]byte, x-from+1)
+		for y := range glyphHeight {
+			for i := range row {
+				row[i] = '.'
+				if minY+y < height && lit(from+i, minY+y) {
+					row[i] = '#'
+				}
+			}
+			g.Rows[y] = string(row)
+		}
+		glyphs = append(glyphs, g)
+	}
+	return glyphs, nil
+}
+
+// ReadText reads a line of block letters, see Segment. Glyphs that are not
+// in the font stop reading with an *UnknownGlyphError, the letters before
+// are returned.
+func ReadText(width, height int, lit func(x, y int) bool) (string, error) {
+	glyphs, err := Segment(width, height, lit)
+	if err != nil {
+		return "", err
+	}
+	text := make([]byte, 0, len(glyphs))
+	for _, g := range glyphs {
+		letter, ok := adventFont[strings.Join(g.Rows, "")]
+		if !ok {
+			return string(text), &UnknownGlyphError{g}
+		}
+		text = append(text, letter)
+	}
+	return string(text), nil
+}
@@ -0,0 +1,153 @@
+package ocr
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// textImage returns the size and pixel function of an image given as rows of
+// '#' and '.'.
+func textImage(rows []string) (int, int, func(x, y int) bool) {
+	return len(rows[0]), len(rows), func(x, y int) bool {
+		return rows[y][x] == '#'
+	}
+}
+
+func TestOCRFont(t *testing.T) {
+	// all letters side by side on the puzzle's pitch, with a blank margin
+	rows := make([]string, glyphHeight+2)
+	var want strings.Builder
+	for _, g := range adventGlyphs {
+		want.WriteByte(g.letter)
+		pad := strings.Repeat(".", glyphPitch-len(g.rows[0]))
+		for y := range rows {
+			if y == 0 || y == glyphHeight+1 {
+				rows[y] += strings.Repeat(".", glyphPitch)
+			} else {
+				rows[y] += g.rows[y-1] + pad
+			}
+		}
+	}
+	for y := range rows {
+		rows[y] = ".." + rows[y]
+	}
+	got, err := ReadText(textImage(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.String() != got {
+		t.Fatalf("want %q but got %q", want.String(), got)
+	}
+}
+
+// TestOCRFontShape makes sure Segment can cut every glyph out of a line.
+func TestOCRFontShape(t *testing.T) {
+	for _, g := range adventGlyphs {
+		width := len(g.rows[0])
+		if width > glyphPitch {
+			t.Fatalf("%c: want at most %d columns but got %d", g.letter, glyphPitch, width)
+		}
+		for x := range width {
+			blank := true
+			for _, row := range g.rows {
+				if len(row) != width {
+					t.Fatalf("%c: want rows %d wide but got %q", g.letter, width, row)
+				}
+				blank = blank && row[x] == '.'
+			}
+			if blank {
+				t.Fatalf("%c: blank column %d", g.letter, x)
+			}
+		}
+	}
+}
+
+// TestOCRTouching reads 5 pixel wide letters that touch the next one.
+func TestOCRTouching(t *testing.T) {
+	rows := []string{
+		"#...##...#####.",
+		"#...##...#...#.",
+		".#.#..#.#...#..",
+		"..#....#...#...",
+		"..#....#..#....",
+		"..#....#..####.",
+	}
+	got, err := ReadText(textImage(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "YYZ"; want != got {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	rows := []string{
+		"...................",
+		"..###...#...#...##.",
+		"...#....#...#....#.",
+		"...#.....#.#.....#.",
+		"...#......#......#.",
+		"...#......#...#..#.",
+		"..###.....#....##..",
+	}
+	glyphs, err := Segment(textImage(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var xs []int
+	var text []byte
+	for _, g := range glyphs {
+		xs = append(xs, g.X)
+		text = append(text, adventFont[strings.Join(g.Rows, "")])
+	}
+	if want := []int{2, 8, 14}; !slices.Equal(want, xs) {
+		t.Fatalf("want glyphs at %v but got %v", want, xs)
+	}
+	if want := "IYJ"; want != string(text) {
+		t.Fatalf("want %q but got %q", want, text)
+	}
+}
+
+func TestOCRErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []string
+	}{
+		{"empty", []string{"....", "...."}},
+		{"unknown glyph", []string{"####", "#..#", "#..#", "#..#", "#..#", "####"}},
+		{"too tall", []string{"#...", "#...", "#...", "#...", "#...", "#...", "####"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadText(textImage(tt.rows)); err == nil {
+				t.Fatal("want error")
+			}
+		})
+	}
+}
+
+func TestOCRUnknownGlyph(t *testing.T) {
+	rows := []string{
+		".##.....####",
+		"#..#....#..#",
+		"#..#....#..#",
+		"####....#..#",
+		"#..#....#..#",
+		"#..#....####",
+	}
+	text, err := ReadText(textImage(rows))
+	if text != "A" {
+		t.Fatalf("want letters before unknown glyph but got %q", text)
+	}
+	var unknown *UnknownGlyphError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("want UnknownGlyphError but got %v", err)
+	}
+	want := "unknown glyph at column 8: #### #..# #..# #..# #..# ####"
+	if err.Error() != want {
+		t.Fatalf("want %q but got %q", want, err)
+	}
+}
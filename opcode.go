@@ -0,0 +1,124 @@
+package adventofcode2019
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxArity is the maximum number of parameters of an instruction. The modes
+// of all parameters plus the two digit opcode must fit into pow10table.
+const maxArity = 8
+
+// Opcode describes an extension instruction for the Intcode machine.
+type Opcode struct {
+	Code   int    // opcode number, 1..98 excluding built-ins
+	Name   string // mnemonic, e.g. "mod"
+	Arity  int    // number of parameters, 0..maxArity
+	Writes []int  // 1-based indices of parameters that are write addresses
+
+	// Exec executes the instruction. args holds one entry per parameter:
+	// the resolved value for read parameters, the resolved address for
+	// write parameters. If Exec does not change the instruction pointer
+	// (e.g. via Jump), the machine advances past the instruction.
+	Exec func(ic *Intcode, args []int) error
+}
+
+var (
+	errBuiltinOpcode = errors.New("built-in opcode cannot be overridden")
+	errOpcodeRange   = errors.New("opcode must be in range 1..98")
+	errArity         = fmt.Errorf("arity must be in range 0..%d", maxArity)
+	errNoExec        = errors.New("missing Exec handler")
+)
+
+// isBuiltin returns true for opcodes 1..9 and 99.
+func isBuiltin(code int) bool {
+	return (code >= 1 && code <= 9) || code == 99
+}
+
+// writes returns true if 1-based parameter n is a write address.
+func (a Opcode) writes(n int) bool {
+	for _, w := range a.Writes {
+		if w == n {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks an Opcode for registration.
+func (a Opcode) validate() error {
+	if isBuiltin(a.Code) {
+		return fmt.Errorf("opcode %d: %w", a.Code, errBuiltinOpcode)
+	}
+	if a.Code < 1 || a.Code > 98 {
+		return fmt.Errorf("opcode %d: %w", a.Code, errOpcodeRange)
+	}
+	if a.Arity < 0 || a.Arity > maxArity {
+		return fmt.Errorf("opcode %d: %w", a.Code, errArity)
+	}
+	for _, w := range a.Writes {
+		if w < 1 || w > a.Arity {
+			return fmt.Errorf("opcode %d: write parameter %d out of range 1..%d",
+				a.Code, w, a.Arity)
+		}
+	}
+	if a.Exec == nil {
+		return fmt.Errorf("opcode %d: %w", a.Code, errNoExec)
+	}
+	return nil
+}
+
+// Register adds an extension instruction to the machine. Registering an
+// already registered extension opcode replaces it, built-in opcodes 1..9 and
+// 99 are protected. Clones created afterwards inherit all registrations.
+func (ic *Intcode) Register(op Opcode) error {
+	if err := op.validate(); err != nil {
+		return err
+	}
+	// copy-on-write, the map is shared between clones
+	ext := make(map[int]Opcode, len(ic.ext)+1)
+	for k, v := range ic.ext {
+		ext[k] = v
+	}
+	ext[op.Code] = op
+	ic.ext = ext
+	return nil
+}
+
+// Jump sets the instruction pointer, for use in Opcode.Exec.
+func (ic *Intcode) Jump(addr int) {
+	ic.ip = addr
+}
+
+// RelBase returns the current relative base.
+func (ic *Intcode) RelBase() int {
+	return ic.relBase
+}
+
+// exec resolves the parameters of an extension instruction and runs it.
+func (ic *Intcode) exec(op Opcode) error {
+	if ic.ip+op.Arity >= len(ic.mem) {
+		return fmt.Errorf("%d parameters exceed memory", op.Arity)
+	}
+	var buf [maxArity]int
+	args := buf[:op.Arity]
+	for n := 1; n <= op.Arity; n++ {
+		if op.writes(n) {
+			args[n-1] = ic.writeAddr(n)
+		} else {
+			args[n-1] = ic.read(n)
+		}
+	}
+	if ic.err != nil {
+		// bad operand, Step reports it
+		return nil
+	}
+	ip := ic.ip
+	if err := op.Exec(ic, args); err != nil {
+		return err
+	}
+	if ic.ip == ip {
+		ic.ip += 1 + op.Arity
+	}
+	return nil
+}
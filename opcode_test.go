@@ -0,0 +1,148 @@
+package adventofcode2019
+
+import (
+	"errors"
+	"testing"
+)
+
+// opMod computes p1 % p2 and stores the result in p3.
+var opMod = Opcode{
+	Code:   20,
+	Name:   "mod",
+	Arity:  3,
+	Writes: []int{3},
+	Exec: func(ic *Intcode, args []int) error {
+		if args[1] == 0 {
+			return errors.New("division by zero")
+		}
+		ic.SetMem(args[2], args[0]%args[1])
+		return nil
+	},
+}
+
+func TestOpcodeRegisterBuiltin(t *testing.T) {
+	ic, err := NewIntcode([]byte("99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 99} {
+		op := opMod
+		op.Code = code
+		if err := ic.Register(op); !errors.Is(err, errBuiltinOpcode) {
+			t.Fatalf("opcode %d: want %v but got %v", code, errBuiltinOpcode, err)
+		}
+	}
+}
+
+func TestOpcodeRegisterInvalid(t *testing.T) {
+	ic, err := NewIntcode([]byte("99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		op   Opcode
+	}{
+		{"code 0", Opcode{Code: 0, Exec: opMod.Exec}},
+		{"code 100", Opcode{Code: 100, Exec: opMod.Exec}},
+		{"arity", Opcode{Code: 20, Arity: maxArity + 1, Exec: opMod.Exec}},
+		{"write range", Opcode{Code: 20, Arity: 1, Writes: []int{2}, Exec: opMod.Exec}},
+		{"no exec", Opcode{Code: 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ic.Register(tt.op); err == nil {
+				t.Fatal("want error but got nil")
+			}
+		})
+	}
+}
+
+func TestOpcodeMod(t *testing.T) {
+	// mod(17, 5) -> mem[7], output mem[7]
+	ic, err := NewIntcode([]byte("21120,17,5,7,4,7,99,0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ic.Register(opMod); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := ic.Run(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 1 || outputs[0] != 2 {
+		t.Fatalf("want [2] but got %v", outputs)
+	}
+}
+
+func TestOpcodeHandlerError(t *testing.T) {
+	ic, err := NewIntcode([]byte("21120,17,0,7,99,0,0,0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ic.Register(opMod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ic.Run(); err == nil {
+		t.Fatal("want error but got nil")
+	}
+	if ic.Step() != Faulted {
+		t.Fatal("want Faulted state")
+	}
+}
+
+func TestOpcodeJump(t *testing.T) {
+	// opcode 21 unconditionally jumps to its parameter, skipping output 1
+	jmp := Opcode{
+		Code:  21,
+		Name:  "jmp",
+		Arity: 1,
+		Exec: func(ic *Intcode, args []int) error {
+			ic.Jump(args[0])
+			return nil
+		},
+	}
+	ic, err := NewIntcode([]byte("121,4,104,1,104,2,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ic.Register(jmp); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := ic.Run(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 1 || outputs[0] != 2 {
+		t.Fatalf("want [2] but got %v", outputs)
+	}
+}
+
+func TestOpcodeIllegal(t *testing.T) {
+	ic, err := NewIntcode([]byte("42,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ic.Run(); err == nil {
+		t.Fatal("want error for illegal opcode but got nil")
+	}
+}
+
+func TestOpcodeCloneInherits(t *testing.T) {
+	ic, err := NewIntcode([]byte("21120,17,5,7,4,7,99,0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ic.Register(opMod); err != nil {
+		t.Fatal(err)
+	}
+	clone := ic.Clone()
+	outputs, err := clone.Run(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 1 || outputs[0] != 2 {
+		t.Fatalf("want [2] but got %v", outputs)
+	}
+}
@@ -50,29 +50,15 @@ func testLines[R comparable](
 	day uint8,
 	filenameFunc func(uint8) string,
 	part1 bool,
-	solver func([]string, bool) R,
+	solver func([]string, bool) (R, error),
 	want R,
 ) {
 	t.Helper()
 	lines := testLinesFromFilename(t, filenameFunc(day))
-	got := solver(lines, part1)
-	if want != got {
-		t.Fatalf("want %v but got %v", want, got)
+	got, err := solver(lines, part1)
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-// testBytes is a generic test helper for day part tests that work directly with []byte.
-func testBytes[R comparable](
-	t *testing.T,
-	day uint8,
-	filenameFunc func(uint8) string,
-	part1 bool,
-	solver func([]byte, bool) R,
-	want R,
-) {
-	t.Helper()
-	buf := fileFromFilename(t, filenameFunc, day)
-	got := solver(buf, part1)
 	if want != got {
 		t.Fatalf("want %v but got %v", want, got)
 	}
@@ -113,25 +99,45 @@ func benchLines[R any](
 	b *testing.B,
 	day uint8,
 	part1 bool,
-	solver func([]string, bool) R,
+	solver func([]string, bool) (R, error),
 ) {
 	b.Helper()
 	lines := testLinesFromFilename(b, filename(day))
 	for b.Loop() {
-		_ = solver(lines, part1)
+		_, _ = solver(lines, part1)
 	}
 }
 
-// benchBytes is a generic benchmark helper for day part benchmarks that work directly with []byte.
-func benchBytes[R any](
-	b *testing.B,
-	day uint8,
-	part1 bool,
-	solver func([]byte, bool) R,
+// errorCase is an input that a solver must reject with the error text want.
+type errorCase[P any] struct {
+	name  string
+	input P
+	part1 bool
+	want  string
+}
+
+// testErrors is a generic test helper that runs each case as a subtest and
+// checks the error text.
+func testErrors[P any, R any](
+	t *testing.T,
+	solver func(P, bool) (R, error),
+	cases []errorCase[P],
 ) {
-	b.Helper()
-	buf := fileFromFilename(b, filename, day)
-	for b.Loop() {
-		_ = solver(buf, part1)
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := solver(tt.input, tt.part1)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("want error %q but got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// fromString adapts a solver for []byte so that error cases can use string
+// literals.
+func fromString[R any](solver func([]byte, bool) (R, error)) func(string, bool) (R, error) {
+	return func(input string, part1 bool) (R, error) {
+		return solver([]byte(input), part1)
 	}
 }
@@ -0,0 +1,143 @@
+package adventofcode2019
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Answer is the solution of one part of a puzzle, either a number or a text
+// such as the letters read from a rendered image.
+type Answer struct {
+	Number uint
+	Text   string
+}
+
+// String returns the answer as it is entered on the website.
+func (a Answer) String() string {
+	if a.Text != "" {
+		return a.Text
+	}
+	return strconv.FormatUint(uint64(a.Number), 10)
+}
+
+// Solver solves one day of the puzzle. Parse reads the puzzle input, Solve
+// may then be called for both parts.
+type Solver interface {
+	Parse(r io.Reader) error
+	Solve(part1 bool) (Answer, error)
+}
+
+// solvers maps day numbers to solver constructors.
+var solvers = map[int]func() Solver{
+	1:  newSolver(io.ReadAll, Day01),
+	2:  newSolver(io.ReadAll, Day02),
+	3:  newSolver(readLines, Day03),
+	4:  newSolver(parseLines(NewDay04), infallible(Day04)),
+	5:  newSolver(io.ReadAll, Day05),
+	6:  newSolver(parseLines(NewDay06), Day06),
+	7:  newSolver(io.ReadAll, Day07),
+	8:  newAnswerSolver(io.ReadAll, Day08),
+	9:  newSolver(io.ReadAll, Day09),
+	10: newSolver(io.ReadAll, Day10),
+	11: newAnswerSolver(io.ReadAll, Day11),
+	12: newSolver(readLines, Day12),
+	13: newSolver(io.ReadAll, Day13),
+	14: newSolver(readLines, Day14),
+	15: newSolver(io.ReadAll, Day15),
+	16: newSolver(io.ReadAll, Day16),
+	17: newSolver(io.ReadAll, Day17),
+	18: newSolver(io.ReadAll, Day18),
+	19: newSolver(io.ReadAll, Day19),
+	20: newSolver(io.ReadAll, Day20),
+	21: newSolver(io.ReadAll, Day21),
+	22: newSolver(readLines, Day22),
+	23: newSolver(io.ReadAll, Day23),
+	24: newSolver(readLines, Day24),
+	25: newSolver(readLines, Day25),
+}
+
+// Days returns the days that have a solver in ascending order.
+func Days() []int {
+	days := make([]int, 0, len(solvers))
+	for day := range solvers {
+		days = append(days, day)
+	}
+	slices.Sort(days)
+	return days
+}
+
+// NewSolver returns a solver for day.
+func NewSolver(day int) (Solver, error) {
+	f, ok := solvers[day]
+	if !ok {
+		return nil, fmt.Errorf("no solver for day %d", day)
+	}
+	return f(), nil
+}
+
+// solver adapts a parse and a solve function of a day to Solver.
+type solver[P any] struct {
+	parse  func(io.Reader) (P, error)
+	solve  func(P, bool) (Answer, error)
+	puzzle P
+}
+
+// newSolver returns a constructor for a day with numeric answers.
+func newSolver[P any](parse func(io.Reader) (P, error), solve func(P, bool) (uint, error)) func() Solver {
+	return newAnswerSolver(parse, func(puzzle P, part1 bool) (Answer, error) {
+		n, err := solve(puzzle, part1)
+		return Answer{Number: n}, err
+	})
+}
+
+// newAnswerSolver returns a constructor for a day that returns an Answer.
+func newAnswerSolver[P any](parse func(io.Reader) (P, error), solve func(P, bool) (Answer, error)) func() Solver {
+	return func() Solver {
+		return &solver[P]{parse: parse, solve: solve}
+	}
+}
+
+func (a *solver[P]) Parse(r io.Reader) error {
+	puzzle, err := a.parse(r)
+	if err != nil {
+		return err
+	}
+	a.puzzle = puzzle
+	return nil
+}
+
+func (a *solver[P]) Solve(part1 bool) (Answer, error) {
+	return a.solve(a.puzzle, part1)
+}
+
+// infallible adapts a solve function that does not return an error.
+func infallible[P any](f func(P, bool) uint) func(P, bool) (uint, error) {
+	return func(puzzle P, part1 bool) (uint, error) {
+		return f(puzzle, part1), nil
+	}
+}
+
+// readLines returns all lines of r.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines, sc.Err()
+}
+
+// parseLines adapts a parser for lines to a reader.
+func parseLines[P any](f func([]string) (P, error)) func(io.Reader) (P, error) {
+	return func(r io.Reader) (P, error) {
+		lines, err := readLines(r)
+		if err != nil {
+			var zero P
+			return zero, err
+		}
+		return f(lines)
+	}
+}
@@ -0,0 +1,89 @@
+package adventofcode2019
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDays(t *testing.T) {
+	days := Days()
+	if len(days) != 25 {
+		t.Fatalf("want 25 days but got %d", len(days))
+	}
+	for i, day := range days {
+		if day != i+1 {
+			t.Fatalf("want day %d at index %d but got %d", i+1, i, day)
+		}
+	}
+}
+
+func TestNewSolverUnknownDay(t *testing.T) {
+	if _, err := NewSolver(26); err == nil {
+		t.Fatal("want error for day 26")
+	}
+}
+
+func TestSolver(t *testing.T) {
+	tests := []struct {
+		day          int
+		part1, part2 uint
+	}{
+		{1, 3231195, 4843929},
+		{4, 1919, 1291},
+		{6, 142497, 301},
+		{12, 7471, 376243355967784},
+		{22, 6289, 58348342289943},
+	}
+	for _, tt := range tests {
+		s, err := NewSolver(tt.day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := fileFromFilename(t, filename, uint8(tt.day))
+		if err := s.Parse(bytes.NewReader(input)); err != nil {
+			t.Fatal(err)
+		}
+		for part1, want := range map[bool]uint{true: tt.part1, false: tt.part2} {
+			got, err := s.Solve(part1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want != got.Number {
+				t.Fatalf("day %d part1=%t: want %d but got %v", tt.day, part1, want, got)
+			}
+		}
+	}
+}
+
+func TestSolverParseError(t *testing.T) {
+	s, err := NewSolver(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Parse(strings.NewReader("COM)A\nA-B\n")); err == nil {
+		t.Fatal("want parse error")
+	}
+}
+
+// TestSolverIntcodeFault feeds a faulting program to every Intcode day,
+// which must fail instead of returning an answer, hanging or panicking.
+func TestSolverIntcodeFault(t *testing.T) {
+	for _, day := range []int{2, 5, 7, 9, 11, 13, 15, 17, 19, 21, 23, 25} {
+		for _, part1 := range []bool{true, false} {
+			if day == 25 && !part1 {
+				continue
+			}
+			s, err := NewSolver(day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Parse(strings.NewReader("42,0,0,0")); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := s.Solve(part1); err == nil {
+				t.Fatalf("day %d part1=%t: want error but got %v", day, part1, got)
+			}
+		}
+	}
+}
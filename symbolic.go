@@ -0,0 +1,648 @@
+package adventofcode2019
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Expr is a polynomial with integer coefficients over symbolic inputs. Input
+// number i is variable i. Exprs are immutable.
+type Expr struct {
+	// terms maps a monomial to its coefficient. A monomial is the sorted
+	// list of its variable indices, one byte each, e.g. "\x00\x00\x01"
+	// for in0²·in1; the empty monomial is the constant term.
+	terms map[string]int
+}
+
+// constExpr returns the constant expression n.
+func constExpr(n int) Expr {
+	if n == 0 {
+		return Expr{}
+	}
+	return Expr{terms: map[string]int{"": n}}
+}
+
+// varExpr returns the expression consisting of variable i.
+func varExpr(i int) Expr {
+	return Expr{terms: map[string]int{string([]byte{byte(i)}): 1}}
+}
+
+// Const returns the value of a constant expression, false if a is symbolic.
+func (a Expr) Const() (int, bool) {
+	for m := range a.terms {
+		if m != "" {
+			return 0, false
+		}
+	}
+	return a.terms[""], true
+}
+
+// Linear returns true if a has no products of variables.
+func (a Expr) Linear() bool {
+	for m := range a.terms {
+		if len(m) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// add returns a + b.
+func (a Expr) add(b Expr) Expr {
+	terms := make(map[string]int, len(a.terms)+len(b.terms))
+	for m, c := range a.terms {
+		terms[m] = c
+	}
+	for m, c := range b.terms {
+		if terms[m] += c; terms[m] == 0 {
+			delete(terms, m)
+		}
+	}
+	return Expr{terms: terms}
+}
+
+// scale returns n * a.
+func (a Expr) scale(n int) Expr {
+	if n == 0 {
+		return Expr{}
+	}
+	terms := make(map[string]int, len(a.terms))
+	for m, c := range a.terms {
+		terms[m] = n * c
+	}
+	return Expr{terms: terms}
+}
+
+// mul returns a * b.
+func (a Expr) mul(b Expr) Expr {
+	terms := make(map[string]int, len(a.terms)*len(b.terms))
+	for m1, c1 := range a.terms {
+		for m2, c2 := range b.terms {
+			buf := []byte(m1 + m2)
+			sort.Slice(buf, func(i, j int) bool { return buf[i] < buf[j] })
+			m := string(buf)
+			if terms[m] += c1 * c2; terms[m] == 0 {
+				delete(terms, m)
+			}
+		}
+	}
+	return Expr{terms: terms}
+}
+
+// Eval evaluates a for concrete input values.
+func (a Expr) Eval(inputs []int) int {
+	sum := 0
+	for m, c := range a.terms {
+		for i := 0; i < len(m); i++ {
+			c *= inputs[m[i]]
+		}
+		sum += c
+	}
+	return sum
+}
+
+// monomials returns the monomials of a in canonical order: by degree, then
+// lexicographically.
+func (a Expr) monomials() []string {
+	ms := make([]string, 0, len(a.terms))
+	for m := range a.terms {
+		ms = append(ms, m)
+	}
+	sort.Slice(ms, func(i, j int) bool {
+		if len(ms[i]) != len(ms[j]) {
+			return len(ms[i]) > len(ms[j])
+		}
+		return ms[i] < ms[j]
+	})
+	return ms
+}
+
+// Format returns a human readable representation, using names for variables
+// if available and in0, in1, ... otherwise.
+func (a Expr) Format(names []string) string {
+	name := func(i byte) string {
+		if int(i) < len(names) {
+			return names[i]
+		}
+		return fmt.Sprintf("in%d", i)
+	}
+	if len(a.terms) == 0 {
+		return "0"
+	}
+	var sb strings.Builder
+	for i, m := range a.monomials() {
+		c := a.terms[m]
+		switch {
+		case i == 0 && c < 0:
+			sb.WriteString("-")
+		case i > 0 && c < 0:
+			sb.WriteString(" - ")
+		case i > 0:
+			sb.WriteString(" + ")
+		}
+		if c < 0 {
+			c = -c
+		}
+		if c != 1 || m == "" {
+			fmt.Fprintf(&sb, "%d", c)
+			if m != "" {
+				sb.WriteString("*")
+			}
+		}
+		for j := 0; j < len(m); j++ {
+			if j > 0 {
+				sb.WriteString("*")
+			}
+			sb.WriteString(name(m[j]))
+		}
+	}
+	return sb.String()
+}
+
+// String implements fmt.Stringer.
+func (a Expr) String() string {
+	return a.Format(nil)
+}
+
+// Relation relates an expression to zero.
+type Relation int
+
+const (
+	LessEqualZero Relation = iota // expr <= 0
+	EqualZero                     // expr == 0
+	NotEqualZero                  // expr != 0
+)
+
+// Constraint is a condition over symbolic inputs.
+type Constraint struct {
+	Expr Expr
+	Rel  Relation
+}
+
+// Holds evaluates the constraint for concrete input values.
+func (a Constraint) Holds(inputs []int) bool {
+	n := a.Expr.Eval(inputs)
+	switch a.Rel {
+	case LessEqualZero:
+		return n <= 0
+	case EqualZero:
+		return n == 0
+	}
+	return n != 0
+}
+
+// not returns the negated constraint. For integers, !(e <= 0) is -e+1 <= 0.
+func (a Constraint) not() Constraint {
+	switch a.Rel {
+	case LessEqualZero:
+		return Constraint{a.Expr.scale(-1).add(constExpr(1)), LessEqualZero}
+	case EqualZero:
+		return Constraint{a.Expr, NotEqualZero}
+	}
+	return Constraint{a.Expr, EqualZero}
+}
+
+// Format returns a human readable representation.
+func (a Constraint) Format(names []string) string {
+	rel := [...]string{" <= 0", " == 0", " != 0"}
+	return a.Expr.Format(names) + rel[a.Rel]
+}
+
+// String implements fmt.Stringer.
+func (a Constraint) String() string {
+	return a.Format(nil)
+}
+
+// Interval is an inclusive range of values for a symbolic input.
+type Interval struct {
+	Min, Max int
+}
+
+// mul returns the interval product.
+func (a Interval) mul(b Interval) Interval {
+	ps := [4]int{a.Min * b.Min, a.Min * b.Max, a.Max * b.Min, a.Max * b.Max}
+	r := Interval{ps[0], ps[0]}
+	for _, p := range ps[1:] {
+		r.Min = min(r.Min, p)
+		r.Max = max(r.Max, p)
+	}
+	return r
+}
+
+// PathStatus tells why the exploration of a path ended.
+type PathStatus int
+
+const (
+	PathHalted      PathStatus = iota // program halted
+	PathInputLimit                    // program wants more than MaxInputs inputs
+	PathStepLimit                     // path exceeds MaxSteps instructions
+	PathUnsupported                   // symbolic address, opcode or jump target
+)
+
+// Path is one feasible execution path of a program.
+type Path struct {
+	Conditions []Constraint // path condition, all constraints hold
+	Outputs    []Expr       // outputs as functions of the inputs
+	Inputs     int          // number of inputs read
+	Status     PathStatus
+	Reason     string // details for PathUnsupported
+}
+
+// Holds returns true if the path is taken for the given inputs.
+func (a Path) Holds(inputs []int) bool {
+	for _, c := range a.Conditions {
+		if !c.Holds(inputs) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExploreOptions bound a symbolic exploration.
+type ExploreOptions struct {
+	MaxInputs int        // inputs per path, 0 means 1
+	MaxSteps  int        // instructions per path, 0 means 100000
+	MaxPaths  int        // number of paths, 0 means 1000
+	Bounds    []Interval // optional value range per input, used for pruning
+}
+
+// errPathLimit is returned when Explore has more than MaxPaths complete or
+// pending paths.
+var errPathLimit = errors.New("path limit exceeded")
+
+// symState is the state of one path under exploration.
+type symState struct {
+	mem     map[int]Expr // symbolic writes, falls back to program memory
+	ip      int
+	relBase int
+	steps   int
+	bounds  []Interval // input bounds, narrowed by path conditions
+	keys    []string   // String() of path conditions, for fast lookup
+	path    Path
+}
+
+// fork returns a copy of s that can be modified independently.
+func (s *symState) fork() *symState {
+	mem := make(map[int]Expr, len(s.mem))
+	for k, v := range s.mem {
+		mem[k] = v
+	}
+	c := *s
+	c.mem = mem
+	c.keys = append([]string(nil), s.keys...)
+	c.path.Conditions = append([]Constraint(nil), s.path.Conditions...)
+	c.path.Outputs = append([]Expr(nil), s.path.Outputs...)
+	return &c
+}
+
+// explorer runs symbolic execution over a concrete program image.
+type explorer struct {
+	prog []int
+	opts ExploreOptions
+}
+
+// load returns the symbolic value at addr.
+func (e *explorer) load(s *symState, addr int) Expr {
+	if v, ok := s.mem[addr]; ok {
+		return v
+	}
+	if addr >= 0 && addr < len(e.prog) {
+		return constExpr(e.prog[addr])
+	}
+	return Expr{}
+}
+
+// interval returns the range of a over the input bounds of s, false if any
+// variable is unbounded.
+func (s *symState) interval(a Expr) (Interval, bool) {
+	var r Interval
+	for m, c := range a.terms {
+		iv := Interval{c, c}
+		for i := 0; i < len(m); i++ {
+			if int(m[i]) >= len(s.bounds) {
+				return r, false
+			}
+			iv = iv.mul(s.bounds[m[i]])
+		}
+		r.Min += iv.Min
+		r.Max += iv.Max
+	}
+	return r, true
+}
+
+// conflicts returns true if c cannot hold on path s.
+func (s *symState) conflicts(c Constraint) bool {
+	if iv, ok := s.interval(c.Expr); ok {
+		switch c.Rel {
+		case LessEqualZero:
+			if iv.Min > 0 {
+				return true
+			}
+		case EqualZero:
+			if iv.Min > 0 || iv.Max < 0 {
+				return true
+			}
+		case NotEqualZero:
+			if iv.Min == 0 && iv.Max == 0 {
+				return true
+			}
+		}
+	}
+	notKey := c.not().String()
+	for i, pc := range s.path.Conditions {
+		if s.keys[i] == notKey {
+			return true
+		}
+		// e1 <= 0 and e2 <= 0 contradict if e1 + e2 is a positive constant
+		if c.Rel == LessEqualZero && pc.Rel == LessEqualZero {
+			if n, ok := c.Expr.add(pc.Expr).Const(); ok && n > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// floorDiv returns a/b rounded towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// assume adds c to the path condition of s and narrows the input bounds
+// for constraints of the form a*x + k <= 0, == 0 and != 0. It returns false
+// if the bounds become empty.
+func (s *symState) assume(c Constraint) bool {
+	s.path.Conditions = append(s.path.Conditions, c)
+	s.keys = append(s.keys, c.String())
+	k, hasConst := c.Expr.terms[""]
+	vars := len(c.Expr.terms)
+	if hasConst {
+		vars--
+	}
+	if !c.Expr.Linear() || vars != 1 {
+		return true
+	}
+	for m, a := range c.Expr.terms {
+		if m == "" {
+			continue
+		}
+		x := int(m[0])
+		if x >= len(s.bounds) {
+			return true
+		}
+		// copy-on-write, bounds are shared between forks
+		bounds := append([]Interval(nil), s.bounds...)
+		iv := &bounds[x]
+		// a*x <= -k, a*x == -k, a*x != -k
+		switch {
+		case c.Rel == NotEqualZero:
+			// only an excluded endpoint narrows an interval
+			if -k%a == 0 && iv.Min == -k/a {
+				iv.Min++
+			}
+			if -k%a == 0 && iv.Max == -k/a {
+				iv.Max--
+			}
+		case c.Rel == EqualZero:
+			if -k%a != 0 {
+				return false
+			}
+			iv.Min = max(iv.Min, -k/a)
+			iv.Max = min(iv.Max, -k/a)
+		case a > 0:
+			iv.Max = min(iv.Max, floorDiv(-k, a))
+		default:
+			iv.Min = max(iv.Min, -floorDiv(-k, -a))
+		}
+		s.bounds = bounds
+		return iv.Min <= iv.Max
+	}
+	return true
+}
+
+// branch splits s on c. It returns the states where c holds and where c does
+// not hold, nil for infeasible ones.
+func (e *explorer) branch(s *symState, c Constraint) (yes, no *symState) {
+	switch {
+	case s.conflicts(c):
+		return nil, s
+	case s.conflicts(c.not()):
+		return s, nil
+	}
+	no = s.fork()
+	if !no.assume(c.not()) {
+		no = nil
+	}
+	if !s.assume(c) {
+		return nil, no
+	}
+	return s, no
+}
+
+// Explore executes the program symbolically from the current machine state,
+// treating every input as a fresh variable. Conditional jumps and
+// comparisons depending on inputs fork the execution. It returns all paths
+// found, and errPathLimit along with the paths completed so far if the
+// number of complete and pending paths exceeds MaxPaths.
+func (ic *Intcode) Explore(opts ExploreOptions) ([]Path, error) {
+	if opts.MaxInputs == 0 {
+		opts.MaxInputs = 1
+	}
+	if opts.MaxSteps == 0 {
+		opts.MaxSteps = 100000
+	}
+	if opts.MaxPaths == 0 {
+		opts.MaxPaths = 1000
+	}
+	e := &explorer{prog: append([]int(nil), ic.mem...), opts: opts}
+	todo := []*symState{{
+		mem:     make(map[int]Expr),
+		ip:      ic.ip,
+		relBase: ic.relBase,
+		bounds:  opts.Bounds,
+	}}
+	var paths []Path
+	for len(todo) > 0 {
+		s := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		next := e.run(s)
+		if next == nil {
+			paths = append(paths, s.path)
+			continue
+		}
+		todo = append(todo, s, next)
+		if len(paths)+len(todo) > opts.MaxPaths {
+			return paths, errPathLimit
+		}
+	}
+	return paths, nil
+}
+
+// run executes s until the path ends (returns nil) or forks (returns the
+// new state, s is the other branch).
+func (e *explorer) run(s *symState) *symState {
+	unsupported := func(format string, args ...any) *symState {
+		s.path.Status = PathUnsupported
+		s.path.Reason = fmt.Sprintf("address %d: ", s.ip) + fmt.Sprintf(format, args...)
+		return nil
+	}
+	for ; s.steps < e.opts.MaxSteps; s.steps++ {
+		raw, ok := e.load(s, s.ip).Const()
+		if !ok {
+			return unsupported("symbolic opcode")
+		}
+		opcode := raw % 100
+		mode := func(n int) int {
+			return (raw / pow10(n+1)) % 10
+		}
+		param := func(n int) Expr {
+			return e.load(s, s.ip+n)
+		}
+		// read returns the value of parameter n, false for symbolic
+		// addresses.
+		read := func(n int) (Expr, bool) {
+			p := param(n)
+			if mode(n) == 1 {
+				return p, true
+			}
+			addr, ok := p.Const()
+			if !ok {
+				return Expr{}, false
+			}
+			if mode(n) == 2 {
+				addr += s.relBase
+			}
+			return e.load(s, addr), true
+		}
+		writeAddr := func(n int) (int, bool) {
+			addr, ok := param(n).Const()
+			if mode(n) == 2 {
+				addr += s.relBase
+			}
+			return addr, ok
+		}
+		// args reads the first n parameters.
+		args := func(n int) ([]Expr, bool) {
+			xs := make([]Expr, n)
+			for i := range xs {
+				x, ok := read(i + 1)
+				if !ok {
+					return nil, false
+				}
+				xs[i] = x
+			}
+			return xs, true
+		}
+
+		switch opcode {
+		case 1, 2, 7, 8:
+			xs, ok := args(2)
+			if !ok {
+				return unsupported("symbolic read address")
+			}
+			addr, ok := writeAddr(3)
+			if !ok {
+				return unsupported("symbolic write address")
+			}
+			switch opcode {
+			case 1:
+				s.mem[addr] = xs[0].add(xs[1])
+			case 2:
+				s.mem[addr] = xs[0].mul(xs[1])
+			default:
+				diff := xs[0].add(xs[1].scale(-1))
+				c := Constraint{diff, EqualZero}
+				if opcode == 7 {
+					// a < b <=> a - b + 1 <= 0
+					c = Constraint{diff.add(constExpr(1)), LessEqualZero}
+				}
+				yes, no := e.branch(s, c)
+				if yes != nil {
+					yes.mem[addr] = constExpr(1)
+					yes.ip += 4
+				}
+				if no != nil {
+					no.mem[addr] = Expr{}
+					no.ip += 4
+				}
+				if yes != nil && no != nil {
+					return no
+				}
+				if no != nil {
+					*s = *no
+				}
+				continue
+			}
+			s.ip += 4
+		case 3:
+			if s.path.Inputs >= e.opts.MaxInputs {
+				s.path.Status = PathInputLimit
+				return nil
+			}
+			addr, ok := writeAddr(1)
+			if !ok {
+				return unsupported("symbolic write address")
+			}
+			s.mem[addr] = varExpr(s.path.Inputs)
+			s.path.Inputs++
+			s.ip += 2
+		case 4:
+			xs, ok := args(1)
+			if !ok {
+				return unsupported("symbolic read address")
+			}
+			s.path.Outputs = append(s.path.Outputs, xs[0])
+			s.ip += 2
+		case 5, 6:
+			xs, ok := args(2)
+			if !ok {
+				return unsupported("symbolic read address")
+			}
+			target, ok := xs[1].Const()
+			if !ok {
+				return unsupported("symbolic jump target")
+			}
+			c := Constraint{xs[0], NotEqualZero}
+			if opcode == 6 {
+				c.Rel = EqualZero
+			}
+			yes, no := e.branch(s, c)
+			if yes != nil {
+				yes.ip = target
+			}
+			if no != nil {
+				no.ip += 3
+			}
+			if yes != nil && no != nil {
+				return no
+			}
+			if no != nil {
+				*s = *no
+			}
+		case 9:
+			xs, ok := args(1)
+			if !ok {
+				return unsupported("symbolic read address")
+			}
+			n, ok := xs[0].Const()
+			if !ok {
+				return unsupported("symbolic relative base")
+			}
+			s.relBase += n
+			s.ip += 2
+		case 99:
+			s.path.Status = PathHalted
+			return nil
+		default:
+			return unsupported("opcode %d", raw)
+		}
+	}
+	s.path.Status = PathStepLimit
+	return nil
+}
@@ -0,0 +1,143 @@
+package adventofcode2019
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExploreEquals8(t *testing.T) {
+	// output 1 if input == 8, 0 otherwise (day 5 example)
+	ic, err := NewIntcode([]byte("3,9,8,9,10,9,4,9,99,-1,8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := ic.Explore(ExploreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("want 2 paths but got %d", len(paths))
+	}
+	want := map[string]string{
+		"in0 - 8 == 0": "1",
+		"in0 - 8 != 0": "0",
+	}
+	for _, p := range paths {
+		if p.Status != PathHalted {
+			t.Fatalf("want halted path but got status %d", p.Status)
+		}
+		if len(p.Conditions) != 1 || len(p.Outputs) != 1 {
+			t.Fatalf("want 1 condition and 1 output but got %v, %v", p.Conditions, p.Outputs)
+		}
+		cond := p.Conditions[0].String()
+		if want[cond] != p.Outputs[0].String() {
+			t.Fatalf("%s: want output %s but got %s", cond, want[cond], p.Outputs[0])
+		}
+	}
+}
+
+func TestExploreLinear(t *testing.T) {
+	// output 3*in0 + in1 - 4
+	ic, err := NewIntcode([]byte("3,0,3,1,1002,0,3,0,1,0,1,0,1001,0,-4,0,4,0,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := ic.Explore(ExploreOptions{MaxInputs: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("want 1 path but got %d", len(paths))
+	}
+	out := paths[0].Outputs[0]
+	if !out.Linear() {
+		t.Fatalf("want linear output but got %s", out)
+	}
+	want := "3*x + y - 4"
+	if got := out.Format([]string{"x", "y"}); want != got {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
+
+func TestExploreUnsupported(t *testing.T) {
+	// jump to input
+	ic, err := NewIntcode([]byte("3,5,105,1,5,0,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := ic.Explore(ExploreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0].Status != PathUnsupported {
+		t.Fatalf("want one unsupported path but got %+v", paths)
+	}
+}
+
+func TestExploreLimits(t *testing.T) {
+	// count down from input, one path per input value
+	ic, err := NewIntcode([]byte("3,0,1006,0,12,1001,0,-1,0,1105,1,2,99"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ic.Explore(ExploreOptions{MaxPaths: 5}); !errors.Is(err, errPathLimit) {
+		t.Fatalf("want %v but got %v", errPathLimit, err)
+	}
+	paths, err := ic.Explore(ExploreOptions{Bounds: []Interval{{0, 9}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 10 {
+		t.Fatalf("want one path per input value 0..9 but got %d", len(paths))
+	}
+}
+
+// TestExploreDay19 checks that the symbolic beam equation agrees with the
+// concrete tractor beam for all points of part 1.
+func TestExploreDay19(t *testing.T) {
+	ic, err := NewIntcode(fileFromFilename(t, filename, 19))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const size = 50
+	paths, err := ic.Explore(ExploreOptions{
+		MaxInputs: 2,
+		Bounds:    []Interval{{0, size - 1}, {0, size - 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for y := range size {
+		for x := range size {
+			in := []int{x, y}
+			var matched []Path
+			for _, p := range paths {
+				if p.Holds(in) {
+					matched = append(matched, p)
+				}
+			}
+			if len(matched) != 1 {
+				t.Fatalf("(%d,%d): want exactly one path but got %d", x, y, len(matched))
+			}
+			outs := matched[0].Outputs
+			if len(outs) != 1 {
+				t.Fatalf("(%d,%d): want one output but got %v", x, y, outs)
+			}
+			got := outs[0].Eval(in) == 1
+			want, err := testPoint(ic, x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want != got {
+				t.Fatalf("(%d,%d): want %v but got %v", x, y, want, got)
+			}
+			if got {
+				count++
+			}
+		}
+	}
+	if count != 160 {
+		t.Fatalf("want 160 beam points but got %d", count)
+	}
+}
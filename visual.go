@@ -0,0 +1,263 @@
+package adventofcode2019
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// Ink is how one kind of cell is drawn, as a rune in text and as a color in
+// images.
+type Ink struct {
+	Rune  rune
+	Color color.RGBA
+}
+
+// Frame is a picture of the state of a puzzle, a grid of cells where each
+// cell is an index into the palette.
+type Frame struct {
+	Width, Height int
+	Palette       []Ink
+	Cells         []uint8 // row major
+	Runes         []rune  // optional, replaces the rune of the ink in text
+}
+
+// NewFrame returns a frame with all cells set to the first ink of palette.
+func NewFrame(width, height int, palette []Ink) *Frame {
+	return &Frame{
+		Width:   width,
+		Height:  height,
+		Palette: palette,
+		Cells:   make([]uint8, width*height),
+	}
+}
+
+// Set paints the cell at x, y, cells outside the frame are ignored.
+func (a *Frame) Set(x, y int, ink uint8) {
+	if x >= 0 && x < a.Width && y >= 0 && y < a.Height {
+		a.Cells[y*a.Width+x] = ink
+	}
+}
+
+// At returns the ink of the cell at x, y.
+func (a *Frame) At(x, y int) uint8 {
+	return a.Cells[y*a.Width+x]
+}
+
+// dark reports whether the ink of cell x, y is closer to black than white.
+func (a *Frame) dark(x, y int) bool {
+	return a.gray(x, y) < 128
+}
+
+func (a *Frame) gray(x, y int) uint8 {
+	return color.GrayModel.Convert(a.Palette[a.At(x, y)].Color).(color.Gray).Y
+}
+
+// WriteASCII writes one line of runes per row.
+func (a *Frame) WriteASCII(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for y := range a.Height {
+		for x := range a.Width {
+			if a.Runes != nil {
+				bw.WriteRune(a.Runes[y*a.Width+x])
+			} else {
+				bw.WriteRune(a.Palette[a.At(x, y)].Rune)
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WritePBM writes a plain portable bitmap, dark cells are black.
+func (a *Frame) WritePBM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P1\n%d %d\n", a.Width, a.Height)
+	for y := range a.Height {
+		for x := range a.Width {
+			if x > 0 {
+				bw.WriteByte(' ')
+			}
+			if a.dark(x, y) {
+				bw.WriteByte('1')
+			} else {
+				bw.WriteByte('0')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WritePGM writes a plain portable graymap with 256 levels.
+func (a *Frame) WritePGM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P2\n%d %d\n255\n", a.Width, a.Height)
+	for y := range a.Height {
+		for x := range a.Width {
+			if x > 0 {
+				bw.WriteByte(' ')
+			}
+			fmt.Fprint(bw, a.gray(x, y))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Image returns the frame with every cell drawn as a square of scale pixels.
+func (a *Frame) Image(scale int) *image.Paletted {
+	scale = max(scale, 1)
+	palette := make(color.Palette, len(a.Palette))
+	for i, ink := range a.Palette {
+		palette[i] = ink.Color
+	}
+	img := image.NewPaletted(image.Rect(0, 0, a.Width*scale, a.Height*scale), palette)
+	for y := range a.Height {
+		for x := range a.Width {
+			ink := a.At(x, y)
+			for dy := range scale {
+				row := img.Pix[(y*scale+dy)*img.Stride:]
+				for dx := range scale {
+					row[x*scale+dx] = ink
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the frame as PNG, see Image for scale.
+func (a *Frame) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, a.Image(scale))
+}
+
+// WriteGIF writes frames as an animated GIF, showing each frame for delay
+// hundredths of a second. See Image for scale.
+func WriteGIF(w io.Writer, frames []*Frame, scale, delay int) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames")
+	}
+	var anim gif.GIF
+	for _, f := range frames {
+		img := f.Image(scale)
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+		anim.Config.Width = max(anim.Config.Width, img.Rect.Dx())
+		anim.Config.Height = max(anim.Config.Height, img.Rect.Dy())
+	}
+	return gif.EncodeAll(w, &anim)
+}
+
+// pointsFrame returns a frame just large enough for all points, and the
+// offset to add to a point to get its cell.
+func pointsFrame[V any](points map[image.Point]V, palette []Ink) (*Frame, image.Point) {
+	if len(points) == 0 {
+		return NewFrame(0, 0, palette), image.Point{}
+	}
+	var r image.Rectangle
+	first := true
+	for p := range points {
+		if first {
+			r = image.Rectangle{p, p.Add(image.Point{1, 1})}
+			first = false
+			continue
+		}
+		r = r.Union(image.Rectangle{p, p.Add(image.Point{1, 1})})
+	}
+	return NewFrame(r.Dx(), r.Dy(), palette), r.Min.Mul(-1)
+}
+
+// gridFrame returns a frame of lines of text, ink maps each byte to its
+// index in palette. The text is kept as is.
+func gridFrame(lines [][]byte, palette []Ink, ink func(byte) uint8) *Frame {
+	var width int
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	f := NewFrame(width, len(lines), palette)
+	f.Runes = make([]rune, width*len(lines))
+	for y, line := range lines {
+		for x := range width {
+			b := byte(' ')
+			if x < len(line) {
+				b = line[x]
+			}
+			f.Set(x, y, ink(b))
+			f.Runes[y*width+x] = rune(b)
+		}
+	}
+	return f
+}
+
+// Colors shared by the palettes of the days.
+var (
+	black  = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	white  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gray   = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	red    = color.RGBA{0xd0, 0x20, 0x20, 0xff}
+	green  = color.RGBA{0x40, 0xe0, 0x40, 0xff}
+	blue   = color.RGBA{0x20, 0x40, 0xd0, 0xff}
+	yellow = color.RGBA{0xf0, 0xc0, 0x20, 0xff}
+)
+
+// mazePalette draws walls, open floor, entrances, and letters for keys,
+// doors and portals.
+var mazePalette = []Ink{{' ', black}, {'#', gray}, {'.', white}, {'@', red}, {'a', yellow}, {'A', blue}}
+
+func mazeInk(b byte) uint8 {
+	switch {
+	case b == '#':
+		return 1
+	case b == '.':
+		return 2
+	case b == '@':
+		return 3
+	case b >= 'a' && b <= 'z':
+		return 4
+	case b >= 'A' && b <= 'Z':
+		return 5
+	}
+	return 0
+}
+
+// visualizers maps day numbers to functions that render a puzzle input as
+// one or more frames.
+var visualizers = map[int]func([]byte) ([]*Frame, error){
+	3:  day03Frames,
+	8:  day08Frames,
+	10: day10Frames,
+	11: day11Frames,
+	13: day13Frames,
+	15: day15Frames,
+	17: day17Frames,
+	18: day18Frames,
+	20: day20Frames,
+	24: day24Frames,
+}
+
+// Visualize renders the state of a day for input. Days with a single state
+// return one frame, days that evolve return a sequence.
+func Visualize(day int, input []byte) ([]*Frame, error) {
+	f, ok := visualizers[day]
+	if !ok {
+		return nil, fmt.Errorf("no visualization for day %d", day)
+	}
+	return f(input)
+}
+
+// VisualizedDays returns the days that have a visualization in ascending
+// order.
+func VisualizedDays() []int {
+	days := make([]int, 0, len(visualizers))
+	for day := range visualizers {
+		days = append(days, day)
+	}
+	slices.Sort(days)
+	return days
+}
@@ -0,0 +1,145 @@
+package adventofcode2019
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testFrame() *Frame {
+	f := NewFrame(3, 2, []Ink{{'.', black}, {'#', white}, {'o', gray}})
+	f.Set(0, 0, 1)
+	f.Set(2, 1, 2)
+	f.Set(3, 0, 1) // outside, ignored
+	return f
+}
+
+func TestFrameText(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(*Frame, *bytes.Buffer) error
+		want  string
+	}{
+		{"ascii", func(f *Frame, w *bytes.Buffer) error { return f.WriteASCII(w) }, "#..\n..o\n"},
+		{"pbm", func(f *Frame, w *bytes.Buffer) error { return f.WritePBM(w) }, "P1\n3 2\n0 1 1\n1 1 0\n"},
+		{"pgm", func(f *Frame, w *bytes.Buffer) error { return f.WritePGM(w) }, "P2\n3 2\n255\n255 0 0\n0 0 128\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(testFrame(), &buf); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != buf.String() {
+				t.Fatalf("want %q but got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestFramePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testFrame().WritePNG(&buf, 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 12 || img.Bounds().Dy() != 8 {
+		t.Fatalf("want 12x8 but got %v", img.Bounds())
+	}
+	if r, _, _, _ := img.At(3, 3).RGBA(); r != 0xffff {
+		t.Fatalf("want white pixel at 3,3 but got %v", img.At(3, 3))
+	}
+	if r, _, _, _ := img.At(4, 3).RGBA(); r != 0 {
+		t.Fatalf("want black pixel at 4,3 but got %v", img.At(4, 3))
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGIF(&buf, []*Frame{testFrame(), testFrame()}, 2, 5); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 || anim.Delay[1] != 5 || anim.Config.Width != 6 {
+		t.Fatalf("want 2 frames 6 pixels wide but got %d, %d", len(anim.Image), anim.Config.Width)
+	}
+	if err := WriteGIF(&buf, nil, 1, 1); err == nil {
+		t.Fatal("want error for no frames")
+	}
+}
+
+func TestVisualize(t *testing.T) {
+	for _, day := range VisualizedDays() {
+		t.Run(filename(uint8(day)), func(t *testing.T) {
+			frames, err := Visualize(day, fileFromFilename(t, filename, uint8(day)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(frames) == 0 || frames[0].Width == 0 || frames[0].Height == 0 {
+				t.Fatalf("want frames but got %d", len(frames))
+			}
+		})
+	}
+}
+
+func TestVisualizeDay11(t *testing.T) {
+	frames, err := Visualize(11, fileFromFilename(t, filename, 11))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := frames[0].WriteASCII(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// top of J and F
+	if !strings.Contains(buf.String(), "## #### ") {
+		t.Fatalf("want JF but got\n%s", buf.String())
+	}
+}
+
+func TestVisualizeDay15(t *testing.T) {
+	frames, err := Visualize(15, fileFromFilename(t, filename, 15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := frames[0].WriteASCII(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "O"); n != 1 {
+		t.Fatalf("want one oxygen system but got %d\n%s", n, buf.String())
+	}
+}
+
+func TestVisualizeDay24(t *testing.T) {
+	frames, err := Visualize(24, fileFromFilename(t, filename, 24))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the last frame is the first repeated layout of part 1
+	last := frames[len(frames)-1]
+	var rating uint
+	for i, ink := range last.Cells {
+		if ink == 1 {
+			rating |= 1 << i
+		}
+	}
+	if want := uint(20751345); want != rating {
+		t.Fatalf("want biodiversity %d but got %d", want, rating)
+	}
+}
+
+func TestVisualizeUnknownDay(t *testing.T) {
+	want := "no visualization for day 1"
+	if _, err := Visualize(1, nil); err == nil || err.Error() != want {
+		t.Fatalf("want error %q but got %v", want, err)
+	}
+}